@@ -1,142 +1,388 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"os/exec"
-	"sort"
+	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
+	"simple-compiler/diagnostic"
 	icg "simple-compiler/intermediate-code-generation"
 	"simple-compiler/lexer"
 	"simple-compiler/parser"
+	"simple-compiler/semantic"
 	"simple-compiler/token"
+	"simple-compiler/types"
 )
 
-func main() {
-	startingTime := time.Now()
+// stage indica até qual fase do compilador um comando executa
+type stage int
+
+const (
+	stageLex stage = iota
+	stageParse
+	stageCheck
+	stageEmitIR
+	stageBuild
+	stageRun
+)
+
+type command struct {
+	name        string
+	stage       stage
+	description string
+}
+
+var commands = []command{
+	{"lex", stageLex, "imprime os tokens gerados pelo lexer"},
+	{"parse", stageParse, "imprime a AST"},
+	{"check", stageCheck, "executa apenas a análise semântica"},
+	{"emit-ir", stageEmitIR, "gera o arquivo LLVM IR (.ll)"},
+	{"build", stageBuild, "gera o executável"},
+	{"run", stageRun, "gera o executável e o executa"},
+}
+
+// options reúne as flags aceitas por todos os comandos
+type options struct {
+	output  string
+	verbose bool
+	noSema  bool
+	timeout time.Duration // Só usado pelo run; zero significa sem limite
+	format  diagnostic.Format
+}
 
+// sourceFile é o arquivo sendo compilado, usado nos diagnósticos
+type sourceFile struct {
+	name string
+	text string
+}
+
+// exitTimeout é o código de saída quando o programa excede o --timeout,
+// o mesmo usado pelo timeout(1) do coreutils
+const exitTimeout = 124
+
+func main() {
 	if len(os.Args) < 2 {
-		fmt.Fprintln(os.Stderr, "Uso: ./main <arquivo> [nome_output] [--run]")
+		printUsage()
 		os.Exit(1)
 	}
 
-	fileName := os.Args[1]
-	outputName := "output"
-	shouldRun := false
+	cmd, ok := findCommand(os.Args[1])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Comando desconhecido: %s\n\n", os.Args[1])
+		printUsage()
+		os.Exit(1)
+	}
 
-	// Parse argumentos opcionais
-	for _, arg := range os.Args[2:] {
-		if arg == "--run" {
-			shouldRun = true
-		} else {
-			outputName = arg
+	var opts options
+	fs := flag.NewFlagSet(cmd.name, flag.ExitOnError)
+	fs.StringVar(&opts.output, "o", "", "caminho do arquivo de saída")
+	fs.BoolVar(&opts.verbose, "v", false, "exibe tokens, AST e LLVM IR gerados")
+	fs.BoolVar(&opts.noSema, "no-sema", false, "não falha o comando check por erros semânticos; os demais comandos nunca geram código de um programa com erros")
+	format := fs.String("diagnostics-format", string(diagnostic.FormatText), "formato dos erros: text, json ou sarif")
+	if cmd.stage == stageRun {
+		fs.DurationVar(&opts.timeout, "timeout", 0, "encerra o programa após a duração informada (ex: 5s)")
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Uso: gopher %s [opções] <arquivo>\n\n%s\n\nOpções:\n", cmd.name, cmd.description)
+		fs.PrintDefaults()
+	}
+
+	files := parseArgs(fs, os.Args[2:])
+	if len(files) != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	var err error
+	if opts.format, err = diagnostic.ParseFormat(*format); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	os.Exit(compile(cmd.stage, files[0], opts))
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Uso: gopher <comando> [opções] <arquivo>")
+	fmt.Fprintln(os.Stderr, "\nComandos:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintln(os.Stderr, "\nUse 'gopher <comando> -h' para ver as opções de cada comando.")
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
 		}
 	}
+	return command{}, false
+}
+
+// parseArgs permite flags antes ou depois do arquivo (ex: "build input.gp -o prog"),
+// já que o pacote flag para de ler no primeiro argumento posicional
+func parseArgs(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			return positional
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// compile executa as fases do compilador até a fase pedida e retorna o
+// código de saída do processo
+func compile(target stage, fileName string, opts options) int {
+	startingTime := time.Now()
 
 	// 1. Ler o arquivo fonte
 	source, err := os.ReadFile(fileName)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Erro ao ler o arquivo '%s': %v\n", fileName, err)
-		os.Exit(1)
+		return 1
 	}
 
+	src := sourceFile{name: fileName, text: string(source)}
+
 	// 2. Análise Léxica
-	l := lexer.New(string(source))
-	var tokens []token.Token
-	for {
-		tok := l.NextToken()
-		tokens = append(tokens, tok)
-		if tok.Type == token.EOF {
-			break
+	tokens := lexer.Tokenize(src.text)
+	if target == stageLex || opts.verbose {
+		printTokens(tokens)
+	}
+	if target == stageLex {
+		return 0
+	}
+
+	// 3. Análise Sintática
+	statements, ok := parse(tokens, src, opts)
+	if !ok {
+		return 1
+	}
+	if target == stageParse || opts.verbose {
+		printAST(statements)
+	}
+	if target == stageParse {
+		return 0
+	}
+
+	// 4. Análise Semântica
+	info, ok := check(statements, src, opts)
+	if target == stageCheck {
+		if !ok {
+			// Com --no-sema os erros são exibidos mas não falham o comando
+			if opts.noSema {
+				return 0
+			}
+			return 1
+		}
+		if opts.format == diagnostic.FormatText {
+			fmt.Println("✅ Nenhum erro encontrado")
+		} else {
+			// Ferramentas esperam um documento válido mesmo sem erros
+			report("", nil, src, opts)
+		}
+		return 0
+	}
+	// O gerador de código supõe um programa bem tipado, então nem --no-sema
+	// deixa um programa com erros semânticos chegar ao LLVM IR
+	if !ok {
+		return 1
+	}
+
+	// 5. Geração de código intermediário
+	generatedCode, ok := generateIR(statements, info, src, opts)
+	if !ok {
+		return 1
+	}
+	if opts.verbose {
+		fmt.Println("\n; Generated LLVM IR")
+		fmt.Println(generatedCode)
+	}
+	if target == stageEmitIR {
+		llPath := opts.output
+		if llPath == "" {
+			llPath = strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName)) + ".ll"
+		}
+		if err := os.WriteFile(llPath, []byte(generatedCode), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "Erro ao escrever o arquivo LLVM IR: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	// 6. Compilar diretamente para executável usando clang
+	outputName := opts.output
+	if outputName == "" {
+		if target == stageRun {
+			// Sem -o, o binário de um run é temporário
+			tmpDir, err := os.MkdirTemp("", "gopher-run-*")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Erro ao criar diretório temporário: %v\n", err)
+				return 1
+			}
+			defer os.RemoveAll(tmpDir)
+			outputName = filepath.Join(tmpDir, "program")
+		} else {
+			outputName = "output"
 		}
 	}
+	if !build(generatedCode, outputName) {
+		return 1
+	}
+
+	if opts.verbose {
+		fmt.Printf("\n⏱️ Tempo de compilação total: %v\n", time.Since(startingTime))
+	}
+	if target == stageBuild {
+		return 0
+	}
+
+	// 7. Executar o binário gerado
+	return run(outputName, opts)
+}
+
+func printTokens(tokens []token.Token) {
 	fmt.Println("\nTokens gerados:")
 	for _, tok := range tokens {
 		fmt.Printf("Type: %-10s Lexeme: %-10s Line: %d Column: %d\n",
 			tok.Type, tok.Lexeme, tok.Line, tok.Column)
 	}
+}
 
-	// 3. Análise Sintática
+func parse(tokens []token.Token, src sourceFile, opts options) ([]parser.Statement, bool) {
 	p := parser.New(tokens)
 	statements := p.Parse()
 
-	// 4. Processamento de erros
 	if len(p.Errors) > 0 {
-		fmt.Println("\nErros encontrados:")
-		sortErrorsByPosition(p.Errors)
-		for _, err := range p.Errors {
-			fmt.Printf("🔴 Linha %d:%d - %s\n", err.Line, err.Column, err.Message)
-		}
-		os.Exit(1)
+		report("Erros encontrados", diagnostic.FromParseErrors(src.name, p.Errors), src, opts)
+		return nil, false
 	}
+	return statements, true
+}
 
-	// 5. Exibir AST
-	if len(statements) > 0 {
-		fmt.Println("\nAST gerada com sucesso:")
-		for _, stmt := range statements {
-			fmt.Println(stmt.String())
-		}
+func printAST(statements []parser.Statement) {
+	if len(statements) == 0 {
+		return
+	}
+	fmt.Println("\nAST gerada com sucesso:")
+	for _, stmt := range statements {
+		fmt.Println(stmt.String())
+	}
+}
+
+func check(statements []parser.Statement, src sourceFile, opts options) (*types.Info, bool) {
+	analyzer := semantic.New(statements)
+	semanticErrors := analyzer.Analyze()
+	if len(semanticErrors) == 0 {
+		return analyzer.Info(), true
 	}
 
-	// 6. Geração de código intermediário
-	generator := icg.NewCodeGenerator()
+	report("Erros semânticos encontrados", diagnostic.FromSemanticErrors(src.name, semanticErrors), src, opts)
+	return nil, false
+}
+
+func generateIR(statements []parser.Statement, info *types.Info, src sourceFile, opts options) (string, bool) {
+	generator := icg.NewCodeGenerator(info)
 	intermediate := generator.GenerateFromAST(statements)
 
 	if errs := generator.GetErrors(); len(errs) > 0 {
-		fmt.Println("\nErros na geração de código:")
-		for _, err := range errs {
-			fmt.Printf("🔴 %s\n", err)
-		}
-		os.Exit(1)
+		report("Erros na geração de código", diagnostic.FromCodegenErrors(src.name, errs), src, opts)
+		return "", false
 	}
+	return intermediate.GenerateLLVM(), true
+}
 
-	generatedCode := intermediate.GenerateLLVM()
-	fmt.Println("\n; Generated LLVM IR")
-	fmt.Println(generatedCode)
+// report exibe os diagnósticos de uma fase no formato escolhido. No formato
+// texto eles vêm precedidos do título da fase
+func report(title string, diags []diagnostic.Diagnostic, src sourceFile, opts options) {
+	diagnostic.Sort(diags)
+	if opts.format == diagnostic.FormatText {
+		fmt.Printf("\n%s:\n", title)
+	}
+	if err := diagnostic.Write(os.Stdout, opts.format, diags, src.text); err != nil {
+		fmt.Fprintf(os.Stderr, "Erro ao escrever os diagnósticos: %v\n", err)
+	}
+}
 
-	// 7. Salvar .ll
-	tmpFile, err := os.CreateTemp("", outputName+"-*.ll")
+// build salva o LLVM IR em um arquivo temporário e o compila com clang
+func build(generatedCode, outputName string) bool {
+	tmpFile, err := os.CreateTemp("", "gopher-*.ll")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Erro ao criar arquivo temporário LLVM IR: %v\n", err)
-		os.Exit(1)
+		return false
 	}
 	defer os.Remove(tmpFile.Name()) // Remove o arquivo temporário ao final
-	
+
 	if _, err := tmpFile.Write([]byte(generatedCode)); err != nil {
 		fmt.Fprintf(os.Stderr, "Erro ao escrever no arquivo temporário: %v\n", err)
-		os.Exit(1)
+		tmpFile.Close()
+		return false
 	}
 	tmpFile.Close()
-	
-	llFile := tmpFile.Name()
-	
-	// 8. Compilar diretamente para executável usando clang
-	cmdClang := exec.Command("clang", llFile, "-o", outputName)
+
+	cmdClang := exec.Command("clang", tmpFile.Name(), "-o", outputName)
 	cmdClang.Stdout = os.Stdout
 	cmdClang.Stderr = os.Stderr
 	if err := cmdClang.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Erro ao compilar com clang: %v\n", err)
-		os.Exit(1)
+		return false
+	}
+	return true
+}
+
+// run executa o programa compilado conectado ao terminal e retorna o código
+// de saída dele, para que scripts possam detectar falhas
+func run(binary string, opts options) int {
+	// Caminhos relativos sem separador seriam procurados no PATH
+	path, err := filepath.Abs(binary)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro ao localizar o executável: %v\n", err)
+		return 1
 	}
 
-	// 9. Executar o binário gerado (se flag --run for usada)
-	if shouldRun {
+	ctx := context.Background()
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	if opts.verbose {
 		fmt.Println("\n🔹 Saída do programa:")
-		cmdExec := exec.Command("./" + outputName)
-		out, _ := cmdExec.CombinedOutput()
-		fmt.Print(string(out))
 	}
+	cmdExec := exec.CommandContext(ctx, path)
+	cmdExec.Stdin = os.Stdin
+	cmdExec.Stdout = os.Stdout
+	cmdExec.Stderr = os.Stderr
 
-	elapsed := time.Since(startingTime)
-	fmt.Printf("\n⏱️ Tempo de compilação total: %v\n", elapsed)
-}
+	err = cmdExec.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		fmt.Fprintf(os.Stderr, "Programa encerrado: tempo limite de %v excedido\n", opts.timeout)
+		return exitTimeout
+	}
+	if err == nil {
+		return 0
+	}
 
-func sortErrorsByPosition(errors []parser.ParseError) {
-	sort.Slice(errors, func(i, j int) bool {
-		if errors[i].Line == errors[j].Line {
-			return errors[i].Column < errors[j].Column
-		}
-		return errors[i].Line < errors[j].Line
-	})
-}
\ No newline at end of file
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		fmt.Fprintf(os.Stderr, "Erro ao executar o programa: %v\n", err)
+		return 1
+	}
+	// Encerrado por sinal: segue a convenção do shell (128 + número do sinal)
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return exitErr.ExitCode()
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"simple-compiler/diagnostic"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeSource grava o código em um arquivo temporário e retorna o caminho
+func writeSource(t *testing.T, src string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "prog.gp")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// captureStdout executa f e retorna o que foi escrito na saída padrão
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+	f()
+	w.Close()
+	return <-output
+}
+
+// Erros semânticos interrompem a compilação antes da geração de código e são
+// exibidos em ordem de posição
+func TestErrosSemanticos(t *testing.T) {
+	file := writeSource(t, "print(y)\nint x = \"texto\"\n")
+	llPath := filepath.Join(t.TempDir(), "prog.ll")
+
+	var code int
+	output := captureStdout(t, func() {
+		code = compile(stageEmitIR, file, options{output: llPath, format: diagnostic.FormatText})
+	})
+	if code != 1 {
+		t.Errorf("código de saída %d, esperava 1", code)
+	}
+	if _, err := os.Stat(llPath); !os.IsNotExist(err) {
+		t.Errorf("emit-ir não deveria ter gerado %s", llPath)
+	}
+
+	first := strings.Index(output, "Linha 1:7 - Identificador não declarado: y")
+	second := strings.Index(output, "Linha 2:5 - Tipo incompatível")
+	if !strings.Contains(output, "Erros semânticos encontrados") || first < 0 || second < first {
+		t.Errorf("saída inesperada:\n%s", output)
+	}
+}
+
+// Com --no-sema o check não falha, mas um programa com erros semânticos nunca
+// chega ao LLVM IR
+func TestNoSema(t *testing.T) {
+	file := writeSource(t, "int x = \"texto\"\nprint(x + 1)\n")
+	llPath := filepath.Join(t.TempDir(), "prog.ll")
+	opts := options{noSema: true, output: llPath, format: diagnostic.FormatText}
+
+	if code := compile(stageCheck, file, opts); code != 0 {
+		t.Errorf("check com --no-sema terminou com código %d, esperava 0", code)
+	}
+	if code := compile(stageCheck, file, options{format: diagnostic.FormatText}); code != 1 {
+		t.Errorf("check sem --no-sema terminou com código %d, esperava 1", code)
+	}
+	if code := compile(stageEmitIR, file, opts); code != 1 {
+		t.Errorf("emit-ir com --no-sema terminou com código %d, esperava 1", code)
+	}
+	if _, err := os.Stat(llPath); !os.IsNotExist(err) {
+		t.Errorf("emit-ir não deveria ter gerado %s", llPath)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		files   []string
+		output  string
+		verbose bool
+	}{
+		{[]string{"prog.gp"}, []string{"prog.gp"}, "", false},
+		{[]string{"-o", "saida", "prog.gp"}, []string{"prog.gp"}, "saida", false},
+		{[]string{"prog.gp", "-o", "saida", "-v"}, []string{"prog.gp"}, "saida", true},
+		{[]string{"a.gp", "b.gp"}, []string{"a.gp", "b.gp"}, "", false},
+	}
+
+	for _, tt := range tests {
+		var opts options
+		fs := flag.NewFlagSet("build", flag.ContinueOnError)
+		fs.StringVar(&opts.output, "o", "", "")
+		fs.BoolVar(&opts.verbose, "v", false, "")
+
+		files := parseArgs(fs, tt.args)
+		if !slices.Equal(files, tt.files) || opts.output != tt.output || opts.verbose != tt.verbose {
+			t.Errorf("parseArgs(%q) = %q, -o %q, -v %v", tt.args, files, opts.output, opts.verbose)
+		}
+	}
+}
+
+func TestFindCommand(t *testing.T) {
+	for _, name := range []string{"lex", "parse", "check", "emit-ir", "build", "run"} {
+		if cmd, ok := findCommand(name); !ok || cmd.name != name {
+			t.Errorf("comando %q não encontrado", name)
+		}
+	}
+	if _, ok := findCommand("--run"); ok {
+		t.Errorf("--run não é um comando")
+	}
+}
+
+// Cada comando para na sua fase e só exibe o resultado dela
+func TestComandos(t *testing.T) {
+	file := writeSource(t, "int x = 1\nprint(x)\n")
+	llPath := filepath.Join(t.TempDir(), "saida.ll")
+	opts := options{output: llPath, format: diagnostic.FormatText}
+
+	tests := []struct {
+		stage   stage
+		want    []string
+		notWant []string
+	}{
+		{stageLex, []string{"Tokens gerados", "Lexeme: print"}, []string{"AST gerada"}},
+		{stageParse, []string{"AST gerada com sucesso"}, []string{"Tokens gerados", "Nenhum erro"}},
+		{stageCheck, []string{"✅ Nenhum erro encontrado"}, []string{"AST gerada"}},
+		{stageEmitIR, nil, []string{"Nenhum erro", "Generated LLVM IR"}},
+	}
+
+	for _, tt := range tests {
+		var code int
+		output := captureStdout(t, func() {
+			code = compile(tt.stage, file, opts)
+		})
+		if code != 0 {
+			t.Errorf("fase %d: código de saída %d\n%s", tt.stage, code, output)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(output, want) {
+				t.Errorf("fase %d: esperava %q na saída:\n%s", tt.stage, want, output)
+			}
+		}
+		for _, notWant := range tt.notWant {
+			if strings.Contains(output, notWant) {
+				t.Errorf("fase %d: não esperava %q na saída:\n%s", tt.stage, notWant, output)
+			}
+		}
+	}
+
+	ir, err := os.ReadFile(llPath)
+	if err != nil {
+		t.Fatalf("emit-ir não escreveu o arquivo -o: %v", err)
+	}
+	if !strings.Contains(string(ir), "define i32 @main()") {
+		t.Errorf("IR inesperado:\n%s", ir)
+	}
+}
+
+// -v volta a exibir tokens, AST e IR em qualquer comando
+func TestVerbose(t *testing.T) {
+	file := writeSource(t, "print(1)\n")
+	opts := options{output: filepath.Join(t.TempDir(), "saida.ll"), verbose: true, format: diagnostic.FormatText}
+	output := captureStdout(t, func() {
+		compile(stageEmitIR, file, opts)
+	})
+	for _, want := range []string{"Tokens gerados", "AST gerada", "; Generated LLVM IR"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("esperava %q na saída:\n%s", want, output)
+		}
+	}
+}
+
+// run devolve o código de saída do programa e o encerra no --timeout
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("clang"); err != nil {
+		t.Skip("clang não encontrado no PATH")
+	}
+
+	tests := []struct {
+		name    string
+		src     string
+		timeout time.Duration
+		code    int
+		output  string
+	}{
+		{"sucesso", "print(\"olá\")\n", 0, 0, "olá\n"},
+		{"exit", "print(1)\nexit(3)\n", 0, 3, "1\n"},
+		{"tempo limite", "while (true) {\n}\n", 200 * time.Millisecond, exitTimeout, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := writeSource(t, tt.src)
+			opts := options{timeout: tt.timeout, format: diagnostic.FormatText}
+			var code int
+			output := captureStdout(t, func() {
+				code = compile(stageRun, file, opts)
+			})
+			if code != tt.code {
+				t.Errorf("código de saída %d, esperava %d", code, tt.code)
+			}
+			if output != tt.output {
+				t.Errorf("saída %q, esperava %q", output, tt.output)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+// Package diagnostic unifica os erros das fases do compilador em um único
+// formato, que pode ser exibido como texto ou exportado em JSON e SARIF.
+package diagnostic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	icg "simple-compiler/intermediate-code-generation"
+	"simple-compiler/parser"
+	"simple-compiler/semantic"
+)
+
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+)
+
+// Códigos identificam a fase que gerou o diagnóstico
+const (
+	CodeSyntax   = "syntax"
+	CodeSemantic = "semantic"
+	CodeCodegen  = "codegen"
+)
+
+// Diagnostic descreve um problema encontrado no código-fonte. Line e Column
+// começam em 1; zero indica que não há posição. EndColumn é exclusivo,
+// apontando para logo após o trecho.
+type Diagnostic struct {
+	Severity  Severity `json:"severity"`
+	Code      string   `json:"code"`
+	File      string   `json:"file"`
+	Line      int      `json:"line"`
+	Column    int      `json:"column"`
+	EndLine   int      `json:"endLine"`
+	EndColumn int      `json:"endColumn"`
+	Message   string   `json:"message"`
+	Notes     []Note   `json:"notes,omitempty"`
+}
+
+// Note aponta para outro trecho relacionado ao diagnóstico (ex: onde uma
+// variável foi declarada)
+type Note struct {
+	Message   string `json:"message"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndColumn int    `json:"endColumn"`
+}
+
+// Format indica como os diagnósticos são exibidos
+type Format string
+
+const (
+	FormatText  Format = "text"
+	FormatJSON  Format = "json"
+	FormatSARIF Format = "sarif"
+)
+
+// ParseFormat valida o valor da flag --diagnostics-format
+func ParseFormat(value string) (Format, error) {
+	switch Format(value) {
+	case FormatText, FormatJSON, FormatSARIF:
+		return Format(value), nil
+	}
+	return "", fmt.Errorf("formato de diagnóstico inválido: %q (use text, json ou sarif)", value)
+}
+
+func newDiagnostic(code, file string, line, column int, lexeme, message string) Diagnostic {
+	d := Diagnostic{
+		Severity: Error,
+		Code:     code,
+		File:     file,
+		Line:     line,
+		Column:   column,
+		Message:  message,
+	}
+	if line > 0 {
+		// O trecho vai até o fim do lexema do token, na mesma linha
+		d.EndLine = line
+		d.EndColumn = endColumn(column, lexeme)
+	}
+	return d
+}
+
+// endColumn calcula a coluna logo após o lexema. Assim como as colunas do
+// lexer, conta bytes. Uma string com quebras de linha é marcada até o fim da
+// primeira linha
+func endColumn(column int, lexeme string) int {
+	if newline := strings.IndexByte(lexeme, '\n'); newline >= 0 {
+		lexeme = lexeme[:newline]
+	}
+	return column + max(len(lexeme), 1)
+}
+
+func FromParseErrors(file string, errs []parser.ParseError) []Diagnostic {
+	diags := make([]Diagnostic, 0, len(errs))
+	for _, err := range errs {
+		diags = append(diags, newDiagnostic(CodeSyntax, file, err.Line, err.Column, err.Token, err.Message))
+	}
+	return diags
+}
+
+func FromSemanticErrors(file string, errs []semantic.SemanticError) []Diagnostic {
+	diags := make([]Diagnostic, 0, len(errs))
+	for _, err := range errs {
+		d := newDiagnostic(CodeSemantic, file, err.Line, err.Column, err.Token, err.Message)
+		for _, note := range err.Notes {
+			d.Notes = append(d.Notes, Note{
+				Message:   note.Message,
+				Line:      note.Line,
+				Column:    note.Column,
+				EndColumn: endColumn(note.Column, note.Token),
+			})
+		}
+		diags = append(diags, d)
+	}
+	return diags
+}
+
+func FromCodegenErrors(file string, errs []icg.CodegenError) []Diagnostic {
+	diags := make([]Diagnostic, 0, len(errs))
+	for _, err := range errs {
+		diags = append(diags, newDiagnostic(CodeCodegen, file, err.Line, err.Column, err.Token, err.Message))
+	}
+	return diags
+}
+
+// Sort ordena os diagnósticos por posição no arquivo
+func Sort(diags []Diagnostic) {
+	sort.SliceStable(diags, func(i, j int) bool {
+		if diags[i].Line == diags[j].Line {
+			return diags[i].Column < diags[j].Column
+		}
+		return diags[i].Line < diags[j].Line
+	})
+}
+
+// Write exibe os diagnósticos no formato pedido. O código-fonte só é usado
+// pelo formato texto, para mostrar os trechos com erro
+func Write(w io.Writer, format Format, diags []Diagnostic, source string) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, diags)
+	case FormatSARIF:
+		return writeSARIF(w, diags)
+	default:
+		writeText(w, diags, source)
+		return nil
+	}
+}
+
+func writeJSON(w io.Writer, diags []Diagnostic) error {
+	if diags == nil {
+		diags = []Diagnostic{} // "[]" em vez de "null" quando não há erros
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(diags)
+}
@@ -0,0 +1,129 @@
+package diagnostic
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	icg "simple-compiler/intermediate-code-generation"
+	"simple-compiler/lexer"
+	"simple-compiler/parser"
+	"simple-compiler/semantic"
+	"strings"
+	"testing"
+)
+
+// analyze devolve os diagnósticos semânticos do código, que não pode ter
+// erros de sintaxe
+func analyze(t *testing.T, src string) []Diagnostic {
+	t.Helper()
+	p := parser.New(lexer.Tokenize(src))
+	statements := p.Parse()
+	if len(p.Errors) > 0 {
+		t.Fatalf("erros de sintaxe: %v", p.Errors)
+	}
+	return FromSemanticErrors("teste.gp", semantic.New(statements).Analyze())
+}
+
+// O trecho de um literal string cobre as aspas e os escapes como escritos
+func TestTrechoDeStringLiteral(t *testing.T) {
+	tests := []struct {
+		src       string
+		column    int
+		endColumn int
+	}{
+		{"if (\"abcdef\") {\n}\n", 5, 13},
+		{"if (\"a\\tb\\u{E9}\") {\n}\n", 5, 17},
+		{"if (\"\") {\n}\n", 5, 7},
+		{"if (\"ab\ncd\") {\n}\n", 5, 8},
+	}
+
+	for _, tt := range tests {
+		diags := analyze(t, tt.src)
+		if len(diags) != 1 {
+			t.Fatalf("%q: esperava 1 diagnóstico, obteve %v", tt.src, diags)
+		}
+		d := diags[0]
+		if d.Line != 1 || d.Column != tt.column || d.EndLine != 1 || d.EndColumn != tt.endColumn {
+			t.Errorf("%q: trecho %d:%d-%d:%d, esperava 1:%d-1:%d", tt.src,
+				d.Line, d.Column, d.EndLine, d.EndColumn, tt.column, tt.endColumn)
+		}
+	}
+}
+
+// Os erros da geração de código apontam para o nó que os causou
+func TestPosicaoDosErrosDaGeracaoDeCodigo(t *testing.T) {
+	src := "func soma() int {\n\treturn 1\n}\nprint(1)\nint y = soma\n"
+	p := parser.New(lexer.Tokenize(src))
+	statements := p.Parse()
+	analyzer := semantic.New(statements)
+	analyzer.Analyze() // Os erros semânticos são ignorados de propósito
+
+	generator := icg.NewCodeGenerator(analyzer.Info())
+	generator.GenerateFromAST(statements)
+	diags := FromCodegenErrors("teste.gp", generator.GetErrors())
+	if len(diags) == 0 {
+		t.Fatalf("esperava erros da geração de código")
+	}
+	for _, d := range diags {
+		if d.Code != CodeCodegen || d.Line != 5 || d.Column != 9 || d.EndColumn != 13 {
+			t.Errorf("diagnóstico inesperado: %+v", d)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, value := range []string{"text", "json", "sarif"} {
+		if format, err := ParseFormat(value); err != nil || string(format) != value {
+			t.Errorf("ParseFormat(%q) = %q, %v", value, format, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Errorf("ParseFormat(\"xml\") deveria falhar")
+	}
+}
+
+// Os erros de todas as fases chegam ao JSON no mesmo formato, ordenados pela
+// posição no arquivo
+func TestWriteJSON(t *testing.T) {
+	diags := FromParseErrors("teste.gp", []parser.ParseError{
+		{Message: "Esperava ')'", Line: 3, Column: 7, Token: "}"},
+	})
+	diags = append(diags, analyze(t, "int x = 1\nx = \"texto\"\n")...)
+	Sort(diags)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, diags, ""); err != nil {
+		t.Fatal(err)
+	}
+	var got []Diagnostic
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("JSON inválido: %v\n%s", err, buf.String())
+	}
+
+	want := []Diagnostic{
+		{
+			Severity: Error, Code: CodeSemantic, File: "teste.gp",
+			Line: 2, Column: 1, EndLine: 2, EndColumn: 2,
+			Message: "Tipo incompatível em atribuição: int = string",
+			Notes:   []Note{{Message: "variável 'x' declarada aqui como int", Line: 1, Column: 5, EndColumn: 6}},
+		},
+		{
+			Severity: Error, Code: CodeSyntax, File: "teste.gp",
+			Line: 3, Column: 7, EndLine: 3, EndColumn: 8,
+			Message: "Esperava ')'",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JSON inesperado:\n%s", buf.String())
+	}
+}
+
+func TestWriteJSONSemErros(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "[]" {
+		t.Errorf("esperava uma lista vazia, obteve %q", got)
+	}
+}
@@ -0,0 +1,123 @@
+package diagnostic
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Estruturas mínimas do SARIF 2.1.0 usadas pelo compilador
+// (https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+	Related   []sarifLocation `json:"relatedLocations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	ID               int                   `json:"id,omitempty"`
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifMessage         `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+func writeSARIF(w io.Writer, diags []Diagnostic) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name: "gopher",
+			Rules: []sarifRule{
+				{ID: CodeSyntax},
+				{ID: CodeSemantic},
+				{ID: CodeCodegen},
+			},
+		}},
+		Results: make([]sarifResult, 0, len(diags)),
+	}
+
+	for _, d := range diags {
+		result := sarifResult{
+			RuleID:  d.Code,
+			Level:   string(d.Severity),
+			Message: sarifMessage{Text: d.Message},
+		}
+		if d.File != "" {
+			result.Locations = []sarifLocation{newSARIFLocation(d.File, d.Line, d.Column, d.EndLine, d.EndColumn)}
+			for i, note := range d.Notes {
+				related := newSARIFLocation(d.File, note.Line, note.Column, note.Line, note.EndColumn)
+				related.ID = i + 1
+				related.Message = &sarifMessage{Text: note.Message}
+				result.Related = append(result.Related, related)
+			}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+func newSARIFLocation(file string, line, column, endLine, endColumn int) sarifLocation {
+	location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: file},
+	}}
+	// SARIF exige linhas a partir de 1; sem posição, só o arquivo
+	if line > 0 {
+		location.PhysicalLocation.Region = &sarifRegion{
+			StartLine:   line,
+			StartColumn: column,
+			EndLine:     endLine,
+			EndColumn:   endColumn,
+		}
+	}
+	return location
+}
@@ -0,0 +1,78 @@
+package diagnostic
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	diags := analyze(t, "int x = 1\nx = \"texto\"\n")
+	diags = append(diags, Diagnostic{Severity: Error, Code: CodeCodegen, File: "teste.gp", Message: "sem posição"})
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatSARIF, diags, ""); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("SARIF inválido: %v\n%s", err, buf.String())
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("documento inesperado:\n%s", buf.String())
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "gopher" || len(run.Tool.Driver.Rules) != 3 {
+		t.Errorf("ferramenta inesperada: %+v", run.Tool.Driver)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("esperava 2 resultados, obteve %d", len(run.Results))
+	}
+
+	result := run.Results[0]
+	if result.RuleID != CodeSemantic || result.Level != "error" || result.Message.Text != diags[0].Message {
+		t.Errorf("resultado inesperado: %+v", result)
+	}
+	if len(result.Locations) != 1 {
+		t.Fatalf("esperava 1 localização, obteve %d", len(result.Locations))
+	}
+	location := result.Locations[0].PhysicalLocation
+	want := sarifRegion{StartLine: 2, StartColumn: 1, EndLine: 2, EndColumn: 2}
+	if location.ArtifactLocation.URI != "teste.gp" || location.Region == nil || *location.Region != want {
+		t.Errorf("localização inesperada: %+v %+v", location.ArtifactLocation, location.Region)
+	}
+
+	// As notas viram localizações relacionadas
+	if len(result.Related) != 1 {
+		t.Fatalf("esperava 1 localização relacionada, obteve %d", len(result.Related))
+	}
+	related := result.Related[0]
+	want = sarifRegion{StartLine: 1, StartColumn: 5, EndLine: 1, EndColumn: 6}
+	if related.ID != 1 || related.Message == nil || related.Message.Text != "variável 'x' declarada aqui como int" ||
+		related.PhysicalLocation.Region == nil || *related.PhysicalLocation.Region != want {
+		t.Errorf("localização relacionada inesperada: %+v", related)
+	}
+
+	// Sem posição, a localização tem só o arquivo: SARIF não aceita linha 0
+	if region := run.Results[1].Locations[0].PhysicalLocation.Region; region != nil {
+		t.Errorf("esperava localização sem região, obteve %+v", region)
+	}
+}
+
+func TestWriteSARIFSemErros(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatSARIF, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("SARIF inválido: %v", err)
+	}
+	// Ferramentas esperam results presente, mesmo vazio
+	runs := doc["runs"].([]any)
+	results, ok := runs[0].(map[string]any)["results"].([]any)
+	if !ok || len(results) != 0 {
+		t.Errorf("esperava results vazio, obteve %s", buf.String())
+	}
+}
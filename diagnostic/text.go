@@ -0,0 +1,81 @@
+package diagnostic
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// writeText exibe cada diagnóstico seguido da linha do código-fonte, com o
+// trecho do erro sublinhado, e das notas relacionadas:
+//
+//	🔴 Linha 3:1 - Tipo incompatível em atribuição: int = string
+//	  |
+//	3 | x = "a"
+//	  | ^
+//	  |
+//	1 | int x = 1
+//	  |     - variável 'x' declarada aqui como int
+func writeText(w io.Writer, diags []Diagnostic, source string) {
+	lines := strings.Split(source, "\n")
+	for _, d := range diags {
+		if d.Line == 0 {
+			fmt.Fprintf(w, "🔴 %s\n", d.Message)
+			continue
+		}
+		fmt.Fprintf(w, "🔴 Linha %d:%d - %s\n", d.Line, d.Column, d.Message)
+
+		// A margem acompanha o maior número de linha exibido
+		width := len(strconv.Itoa(d.Line))
+		for _, note := range d.Notes {
+			if note.Line <= len(lines) {
+				width = max(width, len(strconv.Itoa(note.Line)))
+			}
+		}
+		gutter := strings.Repeat(" ", width) + " |"
+
+		printed := writeSnippet(w, lines, gutter, width, d.Line, d.Column, d.EndColumn, '^', "")
+		for _, note := range d.Notes {
+			if note.Line == 0 || !writeSnippet(w, lines, gutter, width, note.Line, note.Column, note.EndColumn, '-', note.Message) {
+				fmt.Fprintf(w, "%s = nota: %s\n", strings.Repeat(" ", width), note.Message)
+			}
+		}
+		if printed {
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// writeSnippet imprime a linha indicada e sublinha as colunas [column, end)
+// com o marcador. Retorna false se a linha não existir no código-fonte
+func writeSnippet(w io.Writer, lines []string, gutter string, width, line, column, end int, marker rune, label string) bool {
+	if line < 1 || line > len(lines) {
+		return false
+	}
+	text := strings.TrimRight(lines[line-1], "\r")
+
+	// Colunas contam bytes; limita o trecho ao fim da linha
+	start := min(max(column-1, 0), len(text))
+	stop := min(max(end-1, start), len(text))
+
+	// Tabs são mantidos no recuo para que o marcador fique alinhado
+	var indent strings.Builder
+	for _, r := range text[:start] {
+		if r == '\t' {
+			indent.WriteRune('\t')
+		} else {
+			indent.WriteRune(' ')
+		}
+	}
+	underline := strings.Repeat(string(marker), max(utf8.RuneCountInString(text[start:stop]), 1))
+	if label != "" {
+		underline += " " + label
+	}
+
+	fmt.Fprintln(w, gutter)
+	fmt.Fprintf(w, "%*d | %s\n", width, line, text)
+	fmt.Fprintf(w, "%s %s%s\n", gutter, indent.String(), underline)
+	return true
+}
@@ -0,0 +1,93 @@
+package diagnostic
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		diag   Diagnostic
+		want   string
+	}{
+		{
+			name:   "trecho com nota",
+			source: "int x = 1\nx = \"a\"\n",
+			diag: Diagnostic{
+				Line: 2, Column: 1, EndLine: 2, EndColumn: 2,
+				Message: "Tipo incompatível em atribuição: int = string",
+				Notes:   []Note{{Message: "variável 'x' declarada aqui como int", Line: 1, Column: 5, EndColumn: 6}},
+			},
+			want: "🔴 Linha 2:1 - Tipo incompatível em atribuição: int = string\n" +
+				"  |\n" +
+				"2 | x = \"a\"\n" +
+				"  | ^\n" +
+				"  |\n" +
+				"1 | int x = 1\n" +
+				"  |     - variável 'x' declarada aqui como int\n" +
+				"\n",
+		},
+		{
+			name:   "tabs no recuo e acentos no trecho",
+			source: "func f() void {\n\tprint(ação)\n}\n",
+			diag:   Diagnostic{Line: 2, Column: 8, EndLine: 2, EndColumn: 14, Message: "Identificador não declarado: ação"},
+			want: "🔴 Linha 2:8 - Identificador não declarado: ação\n" +
+				"  |\n" +
+				"2 | \tprint(ação)\n" +
+				"  | \t      ^^^^\n" +
+				"\n",
+		},
+		{
+			name:   "margem acompanha o maior número de linha",
+			source: "int a\n\n\n\n\n\n\n\n\nint a\n",
+			diag: Diagnostic{
+				Line: 10, Column: 5, EndLine: 10, EndColumn: 6,
+				Message: "Variável 'a' já declarada",
+				Notes:   []Note{{Message: "declaração anterior aqui", Line: 1, Column: 5, EndColumn: 6}},
+			},
+			want: "🔴 Linha 10:5 - Variável 'a' já declarada\n" +
+				"   |\n" +
+				"10 | int a\n" +
+				"   |     ^\n" +
+				"   |\n" +
+				" 1 | int a\n" +
+				"   |     - declaração anterior aqui\n" +
+				"\n",
+		},
+		{
+			name:   "sem posição",
+			source: "",
+			diag:   Diagnostic{Message: "Erro ao escrever o arquivo"},
+			want:   "🔴 Erro ao escrever o arquivo\n",
+		},
+		{
+			name:   "nota fora do arquivo",
+			source: "x\n",
+			diag: Diagnostic{
+				Line: 1, Column: 1, EndLine: 1, EndColumn: 2,
+				Message: "erro",
+				Notes:   []Note{{Message: "sem trecho", Line: 50, Column: 1, EndColumn: 2}},
+			},
+			want: "🔴 Linha 1:1 - erro\n" +
+				"  |\n" +
+				"1 | x\n" +
+				"  | ^\n" +
+				"  = nota: sem trecho\n" +
+				"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, FormatText, []Diagnostic{tt.diag}, tt.source); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("saída inesperada:\n%s\nesperava:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Package format interpreta as strings de formato do builtin printf. Ele é
+// usado pela análise semântica, para validar os argumentos, e pela geração de
+// código, para traduzir o formato para o printf da libc.
+package format
+
+import (
+	"fmt"
+	"simple-compiler/types"
+	"strings"
+)
+
+// Directive é uma diretiva de formatação, como "%d" ou "%.2f"
+type Directive struct {
+	Verb  byte   // 'd', 'f', 's' ou 't'
+	Text  string // Diretiva completa, incluindo flags, largura e precisão
+	Start int    // Posição do '%' no formato, em bytes
+}
+
+// Type retorna o tipo da linguagem aceito pela diretiva
+func (d Directive) Type() types.Type {
+	switch d.Verb {
+	case 'd':
+		return types.Int
+	case 'f':
+		return types.Float
+	case 's':
+		return types.String
+	default:
+		return types.Bool
+	}
+}
+
+// Parse lê as diretivas de um formato. Cada diretiva tem a forma
+// %[flags][largura][.precisão]verbo, com os verbos:
+//
+//	%d  int
+//	%f  float
+//	%s  string
+//	%t  bool (escrito como true ou false)
+//
+// "%%" escreve um '%' literal e não consome argumentos.
+func Parse(format string) ([]Directive, error) {
+	var directives []Directive
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		start := i
+		i++
+		if i < len(format) && format[i] == '%' {
+			continue
+		}
+
+		for i < len(format) && strings.IndexByte("-+ 0#", format[i]) >= 0 {
+			i++
+		}
+		for i < len(format) && isDigit(format[i]) {
+			i++
+		}
+		if i < len(format) && format[i] == '.' {
+			i++
+			for i < len(format) && isDigit(format[i]) {
+				i++
+			}
+		}
+
+		if i >= len(format) {
+			return nil, fmt.Errorf("diretiva '%s' incompleta no fim do formato", format[start:])
+		}
+		switch format[i] {
+		case 'd', 'f', 's', 't':
+			directives = append(directives, Directive{
+				Verb:  format[i],
+				Text:  format[start : i+1],
+				Start: start,
+			})
+		default:
+			return nil, fmt.Errorf("diretiva desconhecida '%s' (use %%d, %%f, %%s ou %%t)", format[start:i+1])
+		}
+	}
+	return directives, nil
+}
+
+// ToC traduz o formato para o printf da libc, que não conhece %t: booleanos
+// são passados como as strings "true" e "false"
+func ToC(format string, directives []Directive) string {
+	var sb strings.Builder
+	last := 0
+	for _, d := range directives {
+		if d.Verb != 't' {
+			continue
+		}
+		sb.WriteString(format[last:d.Start])
+		sb.WriteString(d.Text[:len(d.Text)-1] + "s")
+		last = d.Start + len(d.Text)
+	}
+	sb.WriteString(format[last:])
+	return sb.String()
+}
+
+func isDigit(ch byte) bool {
+	return '0' <= ch && ch <= '9'
+}
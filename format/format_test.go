@@ -0,0 +1,77 @@
+package format
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string // Texto de cada diretiva
+	}{
+		{"sem diretivas\n", nil},
+		{"x=%d y=%f\n", []string{"%d", "%f"}},
+		{"%s%t", []string{"%s", "%t"}},
+		{"100%% de %d", []string{"%d"}},
+		{"%-5d|%05.2f|%+d|%10s", []string{"%-5d", "%05.2f", "%+d", "%10s"}},
+		{"%5t", []string{"%5t"}},
+	}
+
+	for _, tt := range tests {
+		directives, err := Parse(tt.format)
+		if err != nil {
+			t.Errorf("Parse(%q): erro inesperado: %v", tt.format, err)
+			continue
+		}
+		if len(directives) != len(tt.want) {
+			t.Errorf("Parse(%q): esperava %d diretivas, obteve %v", tt.format, len(tt.want), directives)
+			continue
+		}
+		for i, d := range directives {
+			if d.Text != tt.want[i] || tt.format[d.Start:d.Start+len(d.Text)] != d.Text {
+				t.Errorf("Parse(%q): diretiva %d = %+v, esperava %q", tt.format, i, d, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseInvalido(t *testing.T) {
+	tests := []struct {
+		format string
+		msg    string
+	}{
+		{"%", "diretiva '%' incompleta"},
+		{"x=%5.", "diretiva '%5.' incompleta"},
+		{"%x", "diretiva desconhecida '%x'"},
+		{"%ld", "diretiva desconhecida '%l'"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.format)
+		if err == nil || !strings.Contains(err.Error(), tt.msg) {
+			t.Errorf("Parse(%q): esperava erro com %q, obteve %v", tt.format, tt.msg, err)
+		}
+	}
+}
+
+func TestToC(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"x=%d\n", "x=%d\n"},
+		{"%t e %-6t!", "%s e %-6s!"},
+		{"%%t %t", "%%t %s"},
+	}
+
+	for _, tt := range tests {
+		directives, err := Parse(tt.format)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.format, err)
+		}
+		if got := ToC(tt.format, directives); got != tt.want {
+			t.Errorf("ToC(%q) = %q, esperava %q", tt.format, got, tt.want)
+		}
+	}
+}
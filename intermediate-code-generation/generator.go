@@ -2,36 +2,59 @@ package intermediatecodegeneration
 
 import (
 	"fmt"
+	"math"
+	"simple-compiler/format"
 	"simple-compiler/parser"
+	"simple-compiler/token"
+	"simple-compiler/types"
 	"strconv"
 	"strings"
 )
 
 type CodeGenerator struct {
 	ir           *IntermediateRep
-	symbolTable  map[string]VariableInfo
+	symbolTable  *SymbolTable
 	currentBlock *BasicBlock
 	tempCounter  int
 	labelCounter int
-	stringCounter int
-	errors       []string // Campo errors adicionado
-
+	loopTargets  []loopTarget                // Pilha de laços para break/continue
+	functions    map[string]*types.Signature // Assinaturas conhecidas antes da geração
+	info         *types.Info                 // Tipos encontrados pela análise semântica
+	implicitMain *Function                   // main criado para comandos de nível superior
+	errors       []CodegenError
 }
 
 type VariableInfo struct {
 	Alloca string
-	Type   Type
+	Type   types.Type
+}
+
+// CodegenError é um erro da geração de código, na posição do nó que o causou
+type CodegenError struct {
+	Message string
+	Line    int
+	Column  int
+	Token   string
 }
 
-func NewCodeGenerator() *CodeGenerator {
+// loopTarget guarda para onde break e continue saltam dentro de um laço
+type loopTarget struct {
+	breakLabel    string
+	continueLabel string
+}
+
+// NewCodeGenerator cria um gerador para um programa já analisado, usando os
+// tipos registrados pela análise semântica
+func NewCodeGenerator(info *types.Info) *CodeGenerator {
 	ir := NewIR()
 	cg := &CodeGenerator{
 		ir:           ir,
-		symbolTable:  make(map[string]VariableInfo),
+		symbolTable:  NewSymbolTable(),
 		tempCounter:  0,
 		labelCounter: 0,
-		stringCounter: 0,
-		errors:       make([]string, 0),
+		functions:    make(map[string]*types.Signature),
+		info:         info,
+		errors:       make([]CodegenError, 0),
 	}
 
 	// Não cria bloco inicial automaticamente
@@ -40,24 +63,64 @@ func NewCodeGenerator() *CodeGenerator {
 
 func (cg *CodeGenerator) GenerateFromAST(statements []parser.Statement) *IntermediateRep {
 	// Primeiro processa declarações de função
-	cg.addPrintfSupport()
+	cg.declareRuntime()
+	cg.declareStructs()
+
+	// Registra as assinaturas para que chamadas a funções declaradas mais
+	// adiante no arquivo usem os tipos corretos
+	for _, stmt := range statements {
+		if fnDecl, ok := stmt.(*parser.FunctionDeclaration); ok {
+			if _, exists := cg.functions[fnDecl.Name]; !exists {
+				cg.functions[fnDecl.Name] = cg.signature(fnDecl)
+			}
+		}
+	}
+
+	// Variáveis de nível superior viram globais do módulo, visíveis em
+	// todas as funções
+	var globals []*parser.VariableDeclaration
+	for _, stmt := range statements {
+		if decl, ok := stmt.(*parser.VariableDeclaration); ok {
+			globals = append(globals, decl)
+		}
+	}
+	cg.generateGlobals(globals)
 
 	for _, stmt := range statements {
 		if fnDecl, ok := stmt.(*parser.FunctionDeclaration); ok {
 			cg.generateFunctionDecl(fnDecl)
 		}
 	}
+	if sig, ok := cg.functions["main"]; ok && isVoid(sig.Result) {
+		cg.generateMainWrapper()
+	}
+	// Comandos de nível superior nunca continuam o último bloco gerado
+	cg.currentBlock = nil
 
 	// Depois processa outras declarações
 	for _, stmt := range statements {
-		if _, ok := stmt.(*parser.FunctionDeclaration); !ok {
-			if cg.currentBlock == nil {
-				// Cria uma função main implícita se necessário
-				if !cg.ir.hasFunction("main") {
-					cg.generateImplicitMain()
-				}
-			}
-			cg.generateStatement(stmt)
+		switch stmt.(type) {
+		case *parser.FunctionDeclaration, *parser.VariableDeclaration, *parser.StructDeclaration:
+			continue
+		}
+
+		if _, hasMain := cg.functions["main"]; hasMain {
+			cg.AddError("Comandos no nível superior não são permitidos quando 'main' é declarada", stmt.GetToken())
+			break
+		}
+		if cg.currentBlock == nil {
+			// Cria uma função main implícita se necessário
+			cg.generateImplicitMain()
+		}
+		cg.generateStatement(stmt)
+	}
+
+	// O main implícito termina com sucesso ao fim dos comandos
+	if cg.implicitMain != nil && cg.currentBlock.Terminator == nil {
+		cg.currentBlock.Terminator = &Instruction{
+			Op:   "ret",
+			Type: I32,
+			Args: []string{"0"},
 		}
 	}
 
@@ -72,9 +135,13 @@ func (cg *CodeGenerator) generateStatement(stmt parser.Statement) {
 	switch s := stmt.(type) {
 
 	case *parser.VariableDeclaration:
-		cg.generateVariableDecl(s, false)
+		cg.generateVariableDecl(s)
 	case *parser.AssignmentStatement:
 		cg.generateAssignment(s)
+	case *parser.IndexAssignmentStatement:
+		cg.generateIndexAssignment(s)
+	case *parser.FieldAssignmentStatement:
+		cg.generateFieldAssignment(s)
 	case *parser.IfStatement:
 		cg.generateIfStatement(s)
 	case *parser.WhileStatement:
@@ -85,75 +152,186 @@ func (cg *CodeGenerator) generateStatement(stmt parser.Statement) {
 		cg.generateReturnStatement(s)
 	case *parser.BlockStatement:
 		cg.generateBlock(s)
+	case *parser.BreakStatement:
+		cg.generateLoopJump(s.Token, true)
+	case *parser.ContinueStatement:
+		cg.generateLoopJump(s.Token, false)
 	case *parser.ExpressionStatement:
-		if call, ok := s.Expression.(*parser.CallExpression); ok && call.FunctionName == "print" {
-			cg.generatePrintCall(call)
-		} else {
-			cg.generateExpression(s.Expression)
+		cg.generateExpression(s.Expression)
+	}
+}
+
+func (cg *CodeGenerator) generateVariableDecl(decl *parser.VariableDeclaration) {
+	typ := cg.resolveType(decl.Type)
+	storageType := llvm(typ)
+	alloca := cg.emitAlloca(storageType)
+
+	// O inicializador é avaliado antes da declaração, para que em
+	// "int x = x + 1" o x da direita ainda seja o do escopo externo
+	var val string
+	if decl.Value != nil {
+		val, _ = cg.generateConverted(decl.Value)
+	}
+
+	cg.symbolTable.Declare(decl.Name, VariableInfo{
+		Alloca: alloca,
+		Type:   typ,
+	})
+
+	if decl.Value != nil {
+		cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+			Op:   "store",
+			Type: storageType,
+			Args: []string{val, string(storageType) + "*", alloca},
+		})
+	} else {
+		// Inicializa com valor padrão a cada execução da declaração: a alloca
+		// fica no bloco de entrada, então uma variável declarada em um laço
+		// não pode herdar o valor da iteração anterior
+		cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+			Op:   "store",
+			Type: storageType,
+			Args: []string{cg.zeroValue(typ), string(storageType) + "*", alloca},
+		})
+	}
+}
+
+// emitAlloca reserva espaço para uma variável no bloco de entrada da função.
+// Assim cada declaração tem sua própria alloca, executada uma única vez, mesmo
+// quando a declaração está dentro de um laço.
+func (cg *CodeGenerator) emitAlloca(typ Type) string {
+	alloca := cg.newTemp()
+	entry := cg.ir.CurrentFunction().Blocks[0]
+	entry.Instructions = append(entry.Instructions, Instruction{
+		Op:   "alloca",
+		Type: typ,
+		Dest: alloca,
+	})
+	return alloca
+}
+
+// generateGlobals declara as variáveis globais. Inicializadores constantes
+// vão direto na definição; os demais são calculados por @__init_globals, que
+// roda antes de main via @llvm.global_ctors.
+func (cg *CodeGenerator) generateGlobals(decls []*parser.VariableDeclaration) {
+	var deferred []*parser.VariableDeclaration
+
+	for _, decl := range decls {
+		typ := cg.resolveType(decl.Type)
+		name := "@" + userSymbolPrefix + decl.Name
+
+		initializer, isConstant := cg.zeroValue(typ), true
+		if decl.Value != nil {
+			initializer, isConstant = cg.constantInitializer(decl.Value, typ)
+			if !isConstant {
+				initializer = cg.zeroValue(typ)
+				deferred = append(deferred, decl)
+			}
 		}
+
+		cg.ir.GlobalVars = append(cg.ir.GlobalVars, Instruction{
+			Op:   name,
+			Args: []string{fmt.Sprintf("= global %s %s", llvm(typ), initializer)},
+		})
+		cg.symbolTable.Declare(decl.Name, VariableInfo{
+			Alloca: name,
+			Type:   typ,
+		})
+	}
+
+	if len(deferred) == 0 {
+		return
+	}
+
+	initFn := &Function{
+		Name:       "__init_globals",
+		ReturnType: VOID,
+		Blocks:     []*BasicBlock{{Label: "entry"}},
+	}
+	cg.ir.Functions = append(cg.ir.Functions, initFn)
+	cg.currentBlock = initFn.Blocks[0]
+
+	for _, decl := range deferred {
+		info, _ := cg.symbolTable.Resolve(decl.Name)
+		val, _ := cg.generateConverted(decl.Value)
+		cg.emitStore(val, llvm(info.Type), info.Alloca)
+	}
+	cg.currentBlock.Terminator = &Instruction{
+		Op:   "ret",
+		Type: VOID,
+	}
+	cg.currentBlock = nil
+
+	cg.ir.GlobalVars = append(cg.ir.GlobalVars, Instruction{
+		Op: "@llvm.global_ctors",
+		Args: []string{"= appending global [1 x { i32, void ()*, i8* }] " +
+			"[{ i32, void ()*, i8* } { i32 65535, void ()* @__init_globals, i8* null }]"},
+	})
+}
+
+// constantInitializer tenta escrever a expressão como constante LLVM do tipo
+// informado. Retorna false quando o valor só é conhecido em tempo de execução.
+func (cg *CodeGenerator) constantInitializer(expr parser.Expression, typ types.Type) (string, bool) {
+	switch e := expr.(type) {
+	case *parser.Number:
+		return numberConstant(e.Value, typ), true
+	case *parser.UnaryExpression:
+		if num, ok := e.Right.(*parser.Number); ok && e.Operator == "-" {
+			return numberConstant(-num.Value, typ), true
+		}
+	case *parser.BooleanLiteral:
+		return cg.generateBooleanLiteral(e), true
+	case *parser.StringLiteral:
+		return stringConstant(cg.ir.InternString(e.Value)), true
+	}
+	return "", false
+}
+
+func numberConstant(value float64, typ types.Type) string {
+	if types.Identical(typ, types.Float) {
+		return floatConstant(value)
 	}
+	return strconv.Itoa(int(value))
+}
+
+// floatConstant formata um float no formato hexadecimal do LLVM, único
+// aceito para valores que não são exatos em precisão simples (ex: 0.1)
+func floatConstant(value float64) string {
+	return fmt.Sprintf("0x%016X", math.Float64bits(float64(float32(value))))
 }
 
-func (cg *CodeGenerator) generateVariableDecl(decl *parser.VariableDeclaration, initializeOnly bool) {
-    llvmType := cg.llvmTypeFromParserType(decl.Type)
-    alloca := cg.newTemp()
-
-    // Para strings, usamos i8* no lugar do tipo original
-    storageType := llvmType
-    if decl.Type == "string" {
-        storageType = "i8*"
-    }
-
-    cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
-        Op:   "alloca",
-        Type: storageType,
-        Dest: alloca,
-    })
-
-    cg.symbolTable[decl.Name] = VariableInfo{
-        Alloca: alloca,
-        Type:   llvmType, // Mantemos o tipo original na tabela de símbolos
-    }
-
-    if decl.Value != nil {
-        val := cg.generateExpression(decl.Value)
-        cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
-            Op:   "store",
-            Type: storageType,
-            Args: []string{val, string(storageType) + "*", alloca},
-        })
-    } else if initializeOnly {
-        // Inicializa com valor padrão
-        defaultVal := "0"
-        if decl.Type == "string" {
-            defaultVal = "null"
-        }
-        cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
-            Op:   "store",
-            Type: storageType,
-            Args: []string{defaultVal, string(storageType) + "*", alloca},
-        })
-    }
+// zeroValue retorna o valor inicial de uma variável sem inicializador. Strings
+// começam como "", para que possam ser impressas
+func (cg *CodeGenerator) zeroValue(typ types.Type) string {
+	basic, ok := typ.(*types.Basic)
+	if !ok {
+		// Arrays e structs
+		return "zeroinitializer"
+	}
+	switch basic.Kind {
+	case types.KindFloat:
+		return "0.0"
+	case types.KindString:
+		return stringConstant(cg.ir.InternString(""))
+	}
+	return "0"
 }
 
 func (cg *CodeGenerator) generateAssignment(assign *parser.AssignmentStatement) {
-	info, exists := cg.symbolTable[assign.Name]
+	info, exists := cg.symbolTable.Resolve(assign.Name)
 	if !exists {
+		cg.AddError(fmt.Sprintf("Variável '%s' não declarada", assign.Name), assign.NameToken)
 		return
 	}
 
-	val := cg.generateExpression(assign.Value)
-	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
-		Op:   "store",
-		Type: info.Type,
-		Args: []string{val, string(info.Type) + "*", info.Alloca},
-	})
+	val, _ := cg.generateConverted(assign.Value)
+	cg.emitStore(val, llvm(info.Type), info.Alloca)
 }
 
 func (cg *CodeGenerator) generateExpression(expr parser.Expression) string {
 	switch e := expr.(type) {
 	case *parser.StringLiteral:
-        return cg.generateStringLiteral(e)
+		return cg.generateStringLiteral(e)
 	case *parser.Identifier:
 		return cg.generateIdentifier(e)
 	case *parser.Number:
@@ -166,43 +344,43 @@ func (cg *CodeGenerator) generateExpression(expr parser.Expression) string {
 		return cg.generateUnaryExpr(e)
 	case *parser.CallExpression:
 		return cg.generateCallExpr(e)
+	case *parser.IndexExpression:
+		return cg.generateIndexExpr(e)
+	case *parser.NewArrayExpression:
+		return cg.generateNewArray(e)
+	case *parser.CastExpression:
+		// A conversão explícita foi registrada pela análise semântica como
+		// qualquer outra
+		val, _ := cg.generateConverted(e.Value)
+		return val
+	case *parser.MemberExpression:
+		return cg.generateMemberExpr(e)
+	case *parser.StructLiteral:
+		return cg.generateStructLiteral(e)
 	default:
 		return "0"
 	}
 }
 
 func (cg *CodeGenerator) generateIdentifier(ident *parser.Identifier) string {
-    info, exists := cg.symbolTable[ident.Name]
-    if !exists {
-        return "0"
-    }
-
-    temp := cg.newTemp()
-    
-    // Tratamento especial para strings
-    if info.Type == "string" {
-        cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
-            Op:   "load",
-            Type: "i8*",
-            Args: []string{"i8**", info.Alloca},
-            Dest: temp,
-        })
-    } else {
-        cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
-            Op:   "load",
-            Type: info.Type,
-            Args: []string{string(info.Type) + "*", info.Alloca},
-            Dest: temp,
-        })
-    }
-    return temp
+	info, exists := cg.symbolTable.Resolve(ident.Name)
+	if !exists {
+		cg.AddError(fmt.Sprintf("Identificador não declarado: %s", ident.Name), ident.Token)
+		return "0"
+	}
+
+	// Arrays de tamanho fixo são usados como T[], apontando para a variável
+	if array, ok := info.Type.(*types.Array); ok && array.IsFixed() {
+		return cg.arraySlice(info.Alloca, array)
+	}
+	return cg.emitLoad(llvm(info.Type), info.Alloca)
 }
 
 func (cg *CodeGenerator) generateNumber(num *parser.Number) string {
-	if num.Value == float64(int(num.Value)) {
-		return strconv.Itoa(int(num.Value))
+	if num.IsFloat() {
+		return floatConstant(num.Value)
 	}
-	return fmt.Sprintf("%f", num.Value)
+	return strconv.Itoa(int(num.Value))
 }
 
 func (cg *CodeGenerator) generateBooleanLiteral(boolLit *parser.BooleanLiteral) string {
@@ -218,8 +396,7 @@ func (cg *CodeGenerator) generateUnaryExpr(expr *parser.UnaryExpression) string
 
 	switch expr.Operator {
 	case "-":
-		typ := cg.determineType(expr.Right)
-		if typ == FLOAT {
+		if types.Identical(cg.typeOf(expr.Right), types.Float) {
 			cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
 				Op:   "fneg",
 				Type: FLOAT,
@@ -249,46 +426,40 @@ func (cg *CodeGenerator) generateUnaryExpr(expr *parser.UnaryExpression) string
 }
 
 func (cg *CodeGenerator) generateBinaryExpr(expr *parser.BinaryExpression) string {
-	left := cg.generateExpression(expr.Left)
-	right := cg.generateExpression(expr.Right)
-	temp := cg.newTemp()
-
-	leftType := cg.determineType(expr.Left)
-	rightType := cg.determineType(expr.Right)
-	resultType := leftType
-
-	if leftType == FLOAT || rightType == FLOAT {
-		resultType = FLOAT
-		if leftType != FLOAT {
-			left = cg.generateTypeConversion(left, leftType, FLOAT)
-		}
-		if rightType != FLOAT {
-			right = cg.generateTypeConversion(right, rightType, FLOAT)
-		}
+	// Operadores lógicos não podem avaliar o lado direito antecipadamente
+	if expr.Operator == "&&" || expr.Operator == "||" {
+		return cg.generateLogicalExpr(expr)
 	}
 
+	// Com um operando float, a análise semântica registrou a conversão do
+	// outro, então os dois lados chegam com o mesmo tipo
+	left, leftType := cg.generateConverted(expr.Left)
+	right, rightType := cg.generateConverted(expr.Right)
+	temp := cg.newTemp()
+	isFloat := types.Identical(leftType, types.Float)
+
 	var op string
 	switch expr.Operator {
 	case "+":
-		if resultType == FLOAT {
+		if isFloat {
 			op = "fadd"
 		} else {
 			op = "add"
 		}
 	case "-":
-		if resultType == FLOAT {
+		if isFloat {
 			op = "fsub"
 		} else {
 			op = "sub"
 		}
 	case "*":
-		if resultType == FLOAT {
+		if isFloat {
 			op = "fmul"
 		} else {
 			op = "mul"
 		}
 	case "/":
-		if resultType == FLOAT {
+		if isFloat {
 			op = "fdiv"
 		} else {
 			op = "sdiv"
@@ -301,7 +472,7 @@ func (cg *CodeGenerator) generateBinaryExpr(expr *parser.BinaryExpression) strin
 
 	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
 		Op:   op,
-		Type: resultType,
+		Type: llvm(leftType),
 		Dest: temp,
 		Args: []string{left, right},
 	})
@@ -309,13 +480,75 @@ func (cg *CodeGenerator) generateBinaryExpr(expr *parser.BinaryExpression) strin
 	return temp
 }
 
-func (cg *CodeGenerator) generateComparison(expr *parser.BinaryExpression, left, right string, leftType, rightType Type) string {
+// generateLogicalExpr gera && e || com avaliação em curto-circuito: o lado
+// direito fica em um bloco próprio que só é executado quando necessário, e o
+// resultado é unido no bloco final com um phi.
+func (cg *CodeGenerator) generateLogicalExpr(expr *parser.BinaryExpression) string {
+	prefix := "land"
+	shortCircuitValue := "0" // false && x == false
+	if expr.Operator == "||" {
+		prefix = "lor"
+		shortCircuitValue = "1" // true || x == true
+	}
+
+	rhsLabel := cg.newLabel(prefix + ".rhs")
+	endLabel := cg.newLabel(prefix + ".end")
+
+	left := cg.generateExpression(expr.Left)
+	// O lado esquerdo pode ter criado novos blocos (ex: outro && aninhado)
+	leftBlock := cg.currentBlock.Label
+
+	if expr.Operator == "&&" {
+		cg.currentBlock.Terminator = &Instruction{
+			Op:   "br",
+			Args: []string{left, rhsLabel, endLabel},
+		}
+	} else {
+		cg.currentBlock.Terminator = &Instruction{
+			Op:   "br",
+			Args: []string{left, endLabel, rhsLabel},
+		}
+	}
+
+	rhsBlock := &BasicBlock{Label: rhsLabel}
+	cg.ir.CurrentFunction().Blocks = append(cg.ir.CurrentFunction().Blocks, rhsBlock)
+	cg.currentBlock = rhsBlock
+	right := cg.generateExpression(expr.Right)
+	rightBlock := cg.currentBlock.Label
+	cg.branchTo(endLabel)
+
+	endBlock := &BasicBlock{Label: endLabel}
+	cg.ir.CurrentFunction().Blocks = append(cg.ir.CurrentFunction().Blocks, endBlock)
+	cg.currentBlock = endBlock
+
+	temp := cg.newTemp()
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+		Op:   "phi",
+		Type: I1,
+		Dest: temp,
+		Args: []string{
+			fmt.Sprintf("[ %s, %%%s ]", shortCircuitValue, leftBlock),
+			fmt.Sprintf("[ %s, %%%s ]", right, rightBlock),
+		},
+	})
+
+	return temp
+}
+
+func (cg *CodeGenerator) generateComparison(expr *parser.BinaryExpression, left, right string, leftType, rightType types.Type) string {
+	// Strings são comparadas pelo conteúdo: strcmp retorna 0 quando são iguais
+	if types.Identical(leftType, types.String) {
+		left = cg.callRuntime("strcmp", "i8* "+cg.nonNullString(left), "i8* "+cg.nonNullString(right))
+		right = "0"
+		leftType, rightType = types.Int, types.Int
+	}
+
 	temp := cg.newTemp()
 	var op string
 	var predicate string // Novo: armazenar o predicado separadamente
 	var cmpType Type = I1
 
-	if leftType == FLOAT || rightType == FLOAT {
+	if types.Identical(leftType, types.Float) || types.Identical(rightType, types.Float) {
 		op = "fcmp"
 		switch expr.Operator {
 		case "<":
@@ -353,92 +586,87 @@ func (cg *CodeGenerator) generateComparison(expr *parser.BinaryExpression, left,
 	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
 		Op:   op,
 		Type: cmpType,
-		Args: []string{predicate, string(leftType), left, right},
+		Args: []string{predicate, string(llvm(leftType)), left, right},
 		Dest: temp,
 	})
 
 	return temp
 }
 
-func (cg *CodeGenerator) generateTypeConversion(value string, fromType, toType Type) string {
-	temp := cg.newTemp()
+// generateTypeConversion converte um número entre int e float. Os demais
+// pares de tipos não precisam de instrução
+func (cg *CodeGenerator) generateTypeConversion(value string, fromType, toType types.Type) string {
 	var op string
-
-	if fromType == I32 && toType == FLOAT {
+	if types.Identical(fromType, types.Int) && types.Identical(toType, types.Float) {
 		op = "sitofp"
-	} else if fromType == FLOAT && toType == I32 {
+	} else if types.Identical(fromType, types.Float) && types.Identical(toType, types.Int) {
 		op = "fptosi"
 	} else {
 		return value
 	}
+	return cg.emitCast(op, value, llvm(fromType), llvm(toType))
+}
 
+// emitCast gera uma instrução de conversão, no formato
+// %dest = sitofp i32 %valor to float
+func (cg *CodeGenerator) emitCast(op, value string, fromType, toType Type) string {
+	temp := cg.newTemp()
 	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
 		Op:   op,
 		Type: toType,
 		Dest: temp,
-		Args: []string{value},
+		Args: []string{string(fromType), value},
 	})
-
 	return temp
 }
 
 func (cg *CodeGenerator) generateCallExpr(call *parser.CallExpression) string {
+	if call.FunctionName == "exit" {
+		return cg.generateExitCall(call)
+	}
+	if call.FunctionName == "printf" {
+		cg.generatePrintfCall(call)
+		return "0"
+	}
 	if call.FunctionName == "print" {
-		if len(call.Arguments) != 1 {
-			cg.AddError("print requer exatamente 1 argumento")
-			return "0"
-		}
-
-		arg := cg.generateExpression(call.Arguments[0])
-		argType := cg.determineType(call.Arguments[0])
-
-		fmtStr := cg.newTemp()
-		formatStr := "@.str" // padrão para int
-		if argType == FLOAT {
-			formatStr = "@.strf"
-		}
-
-		cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
-			Op:   "getelementptr",
-			Dest: fmtStr,
-			Args: []string{fmt.Sprintf("[4 x i8], [4 x i8]* %s, i32 0, i32 0", formatStr)},
-		})
-
-		cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
-			Op:   "call",
-			Type: I32,
-			Args: []string{fmt.Sprintf("i32 (i8*, ...) @printf(i8* %s, %s %s)", fmtStr, argType, arg)},
-		})
+		cg.generatePrintCall(call)
+		return "0"
+	}
+	if call.FunctionName == "len" {
+		return cg.generateLenCall(call)
+	}
 
+	sig, exists := cg.functions[call.FunctionName]
+	if !exists {
+		cg.AddError(fmt.Sprintf("Função '%s' não declarada", call.FunctionName), call.Token)
 		return "0"
 	}
-	// Restante da implementação original...
+
 	temp := cg.newTemp()
 	args := make([]string, len(call.Arguments))
 
-	// Processa os argumentos
+	// Processa os argumentos, já convertidos para o tipo do parâmetro
+	// (ex: int passado para float)
 	for i, arg := range call.Arguments {
-		args[i] = cg.generateExpression(arg)
+		val, argType := cg.generateConverted(arg)
+		args[i] = fmt.Sprintf("%s %s", llvm(argType), val)
 	}
 
-	// Obtém o tipo de retorno da função
-	returnType := cg.getFunctionReturnType(call.FunctionName)
+	returnType := llvm(sig.Result)
 
-	// Formata os argumentos com seus tipos
-	typedArgs := make([]string, len(args))
-	for i, arg := range args {
-		argType := cg.determineType(call.Arguments[i])
-		typedArgs[i] = fmt.Sprintf("%s %s", argType, arg)
-	}
-
-	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+	callInst := Instruction{
 		Op:   "call",
 		Type: returnType,
 		Dest: temp,
 		Args: []string{
-			fmt.Sprintf("%s @%s(%s)", returnType, call.FunctionName, strings.Join(typedArgs, ", ")),
+			fmt.Sprintf("%s @%s(%s)", returnType, cg.llvmFunctionName(call.FunctionName), strings.Join(args, ", ")),
 		},
-	})
+	}
+	// Chamadas void não produzem valor e não podem ter destino
+	if isVoid(sig.Result) {
+		callInst.Dest = ""
+	}
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, callInst)
 
 	return temp
 }
@@ -458,10 +686,7 @@ func (cg *CodeGenerator) generateIfStatement(ifStmt *parser.IfStatement) {
 	cg.ir.CurrentFunction().Blocks = append(cg.ir.CurrentFunction().Blocks, thenBlock)
 	cg.currentBlock = thenBlock
 	cg.generateBlock(ifStmt.Body)
-	thenBlock.Terminator = &Instruction{
-		Op:   "br",
-		Args: []string{endLabel},
-	}
+	cg.branchTo(endLabel)
 
 	elseBlock := &BasicBlock{Label: elseLabel}
 	cg.ir.CurrentFunction().Blocks = append(cg.ir.CurrentFunction().Blocks, elseBlock)
@@ -469,16 +694,26 @@ func (cg *CodeGenerator) generateIfStatement(ifStmt *parser.IfStatement) {
 	if ifStmt.ElseBody != nil {
 		cg.generateBlock(ifStmt.ElseBody)
 	}
-	elseBlock.Terminator = &Instruction{
-		Op:   "br",
-		Args: []string{endLabel},
-	}
+	cg.branchTo(endLabel)
 
 	endBlock := &BasicBlock{Label: endLabel}
 	cg.ir.CurrentFunction().Blocks = append(cg.ir.CurrentFunction().Blocks, endBlock)
 	cg.currentBlock = endBlock
 }
 
+// branchTo encerra o bloco atual com um salto incondicional, a menos que ele
+// já tenha um terminador (ex: um return no fim do corpo). O bloco atual pode
+// não ser o bloco onde o corpo começou quando há estruturas aninhadas.
+func (cg *CodeGenerator) branchTo(label string) {
+	if cg.currentBlock.Terminator != nil {
+		return
+	}
+	cg.currentBlock.Terminator = &Instruction{
+		Op:   "br",
+		Args: []string{label},
+	}
+}
+
 func (cg *CodeGenerator) generateWhileStatement(whileStmt *parser.WhileStatement) {
 	condLabel := cg.newLabel("while.cond")
 	bodyLabel := cg.newLabel("while.body")
@@ -493,7 +728,8 @@ func (cg *CodeGenerator) generateWhileStatement(whileStmt *parser.WhileStatement
 	cg.ir.CurrentFunction().Blocks = append(cg.ir.CurrentFunction().Blocks, condBlock)
 	cg.currentBlock = condBlock
 	cond := cg.generateExpression(whileStmt.Condition)
-	condBlock.Terminator = &Instruction{
+	// A condição pode ter criado novos blocos (ex: && e ||)
+	cg.currentBlock.Terminator = &Instruction{
 		Op:   "br",
 		Args: []string{cond, bodyLabel, endLabel},
 	}
@@ -501,11 +737,10 @@ func (cg *CodeGenerator) generateWhileStatement(whileStmt *parser.WhileStatement
 	bodyBlock := &BasicBlock{Label: bodyLabel}
 	cg.ir.CurrentFunction().Blocks = append(cg.ir.CurrentFunction().Blocks, bodyBlock)
 	cg.currentBlock = bodyBlock
+	cg.pushLoop(endLabel, condLabel)
 	cg.generateBlock(whileStmt.Body)
-	bodyBlock.Terminator = &Instruction{
-		Op:   "br",
-		Args: []string{condLabel},
-	}
+	cg.popLoop()
+	cg.branchTo(condLabel)
 
 	endBlock := &BasicBlock{Label: endLabel}
 	cg.ir.CurrentFunction().Blocks = append(cg.ir.CurrentFunction().Blocks, endBlock)
@@ -518,6 +753,10 @@ func (cg *CodeGenerator) generateForStatement(forStmt *parser.ForStatement) {
 	stepLabel := cg.newLabel("for.step")
 	endLabel := cg.newLabel("for.end")
 
+	// Variáveis declaradas na inicialização só existem dentro do for
+	cg.symbolTable.PushScope()
+	defer cg.symbolTable.PopScope()
+
 	if forStmt.Init != nil {
 		cg.generateStatement(forStmt.Init)
 	}
@@ -533,7 +772,7 @@ func (cg *CodeGenerator) generateForStatement(forStmt *parser.ForStatement) {
 
 	if forStmt.Condition != nil {
 		cond := cg.generateExpression(forStmt.Condition)
-		condBlock.Terminator = &Instruction{
+		cg.currentBlock.Terminator = &Instruction{
 			Op:   "br",
 			Args: []string{cond, bodyLabel, endLabel},
 		}
@@ -547,11 +786,11 @@ func (cg *CodeGenerator) generateForStatement(forStmt *parser.ForStatement) {
 	bodyBlock := &BasicBlock{Label: bodyLabel}
 	cg.ir.CurrentFunction().Blocks = append(cg.ir.CurrentFunction().Blocks, bodyBlock)
 	cg.currentBlock = bodyBlock
+	// continue no for precisa executar a atualização, então salta para o step
+	cg.pushLoop(endLabel, stepLabel)
 	cg.generateBlock(forStmt.Body)
-	bodyBlock.Terminator = &Instruction{
-		Op:   "br",
-		Args: []string{stepLabel},
-	}
+	cg.popLoop()
+	cg.branchTo(stepLabel)
 
 	stepBlock := &BasicBlock{Label: stepLabel}
 	cg.ir.CurrentFunction().Blocks = append(cg.ir.CurrentFunction().Blocks, stepBlock)
@@ -559,20 +798,46 @@ func (cg *CodeGenerator) generateForStatement(forStmt *parser.ForStatement) {
 	if forStmt.Update != nil {
 		cg.generateStatement(forStmt.Update)
 	}
-	stepBlock.Terminator = &Instruction{
-		Op:   "br",
-		Args: []string{condLabel},
-	}
+	cg.branchTo(condLabel)
 
 	endBlock := &BasicBlock{Label: endLabel}
 	cg.ir.CurrentFunction().Blocks = append(cg.ir.CurrentFunction().Blocks, endBlock)
 	cg.currentBlock = endBlock
 }
 
+// pushLoop registra os destinos de break e continue do laço sendo gerado
+func (cg *CodeGenerator) pushLoop(breakLabel, continueLabel string) {
+	cg.loopTargets = append(cg.loopTargets, loopTarget{
+		breakLabel:    breakLabel,
+		continueLabel: continueLabel,
+	})
+}
+
+func (cg *CodeGenerator) popLoop() {
+	cg.loopTargets = cg.loopTargets[:len(cg.loopTargets)-1]
+}
+
+// generateLoopJump gera o salto de um break ou continue para o laço mais interno
+func (cg *CodeGenerator) generateLoopJump(keyword token.Token, isBreak bool) {
+	if len(cg.loopTargets) == 0 {
+		cg.AddError(fmt.Sprintf("'%s' fora de um laço", keyword.Lexeme), keyword)
+		return
+	}
+
+	target := cg.loopTargets[len(cg.loopTargets)-1]
+	label := target.continueLabel
+	if isBreak {
+		label = target.breakLabel
+	}
+	cg.branchTo(label)
+	cg.startUnreachableBlock(keyword.Lexeme + ".after")
+}
+
 func (cg *CodeGenerator) generateReturnStatement(ret *parser.ReturnStatement) {
 	if ret.Value != nil {
-		val := cg.generateExpression(ret.Value)
-		retType := cg.determineType(ret.Value)
+		// Já convertido para o tipo declarado da função (ex: int retornado em float)
+		val, _ := cg.generateConverted(ret.Value)
+		retType := cg.ir.CurrentFunction().ReturnType
 		cg.currentBlock.Terminator = &Instruction{
 			Op:   "ret",
 			Type: retType,
@@ -584,6 +849,16 @@ func (cg *CodeGenerator) generateReturnStatement(ret *parser.ReturnStatement) {
 			Type: VOID,
 		}
 	}
+	cg.startUnreachableBlock("return.after")
+}
+
+// startUnreachableBlock abre o bloco que recebe os comandos escritos depois
+// de um terminador (return, break, continue ou exit). Eles nunca executam,
+// mas cada bloco LLVM só pode ter um terminador
+func (cg *CodeGenerator) startUnreachableBlock(prefix string) {
+	afterBlock := &BasicBlock{Label: cg.newLabel(prefix)}
+	cg.ir.CurrentFunction().Blocks = append(cg.ir.CurrentFunction().Blocks, afterBlock)
+	cg.currentBlock = afterBlock
 }
 
 func (cg *CodeGenerator) generateBlock(block *parser.BlockStatement) {
@@ -591,63 +866,55 @@ func (cg *CodeGenerator) generateBlock(block *parser.BlockStatement) {
 		return
 	}
 
+	cg.symbolTable.PushScope()
 	for _, stmt := range block.Statements {
 		cg.generateStatement(stmt)
 	}
+	cg.symbolTable.PopScope()
 }
 func (cg *CodeGenerator) generateFunctionDecl(decl *parser.FunctionDeclaration) {
 	// Verifica se a função já existe
-	if cg.ir.hasFunction(decl.Name) {
-		cg.AddError(fmt.Sprintf("Função '%s' já declarada", decl.Name))
+	if cg.ir.hasFunction(cg.llvmFunctionName(decl.Name)) {
+		cg.AddError(fmt.Sprintf("Função '%s' já declarada", decl.Name), decl.Token)
 		return
 	}
 
-	// Converte tipo de retorno
-	returnType := cg.llvmTypeFromParserType(decl.ReturnType)
-	if decl.Name == "main" && decl.ReturnType == "void" {
-		returnType = VOID
-	}
+	sig := cg.signature(decl)
 
 	// Prepara parâmetros
 	var params []Param
-	for _, param := range decl.Parameters {
+	for i, param := range decl.Parameters {
 		params = append(params, Param{
-			Name: param.Name,
-			Type: cg.llvmTypeFromParserType(param.Type),
+			Name: paramPrefix + param.Name,
+			Type: llvm(sig.Params[i]),
 		})
 	}
 
 	// Cria função no IR
 	fn := &Function{
-		Name:       decl.Name,
-		ReturnType: returnType,
+		Name:       cg.llvmFunctionName(decl.Name),
+		ReturnType: llvm(sig.Result),
 		Params:     params,
 		Blocks:     []*BasicBlock{{Label: "entry"}},
 	}
 	cg.ir.Functions = append(cg.ir.Functions, fn)
 	cg.currentBlock = fn.Blocks[0]
 
+	// Parâmetros e corpo ficam em um escopo próprio da função
+	cg.symbolTable.PushScope()
+	defer cg.symbolTable.PopScope()
+
 	// Gera alocações para parâmetros
-	for _, param := range params {
-		alloca := cg.newTemp()
-		cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
-			Op:   "alloca",
-			Type: param.Type,
-			Dest: alloca,
-		})
+	for i, param := range params {
+		alloca := cg.emitAlloca(param.Type)
 
 		// Armazena o valor do parâmetro
-		paramReg := "%" + param.Name
-		cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
-			Op:   "store",
-			Type: param.Type,
-			Args: []string{paramReg, string(param.Type) + "*", alloca},
-		})
+		cg.emitStore("%"+param.Name, param.Type, alloca)
 
-		cg.symbolTable[param.Name] = VariableInfo{
+		cg.symbolTable.Declare(decl.Parameters[i].Name, VariableInfo{
 			Alloca: alloca,
-			Type:   param.Type,
-		}
+			Type:   sig.Params[i],
+		})
 	}
 
 	// Gera corpo da função
@@ -656,64 +923,103 @@ func (cg *CodeGenerator) generateFunctionDecl(decl *parser.FunctionDeclaration)
 	}
 	cg.generateBlock(block)
 
-	// Adiciona retorno padrão se necessário
+	// Funções void retornam implicitamente no fim. Nas demais, a análise
+	// semântica garante que todo caminho tem return, então o fim é inalcançável
 	if cg.currentBlock.Terminator == nil {
-		if decl.ReturnType == "void" {
+		if isVoid(sig.Result) {
 			cg.currentBlock.Terminator = &Instruction{
 				Op:   "ret",
 				Type: VOID,
 			}
 		} else {
 			cg.currentBlock.Terminator = &Instruction{
-				Op:   "ret",
-				Type: returnType,
-				Args: []string{"0"},
+				Op: "unreachable",
 			}
 		}
 	}
 }
 
-func (cg *CodeGenerator) determineType(expr parser.Expression) Type {
-	switch e := expr.(type) {
-	case *parser.StringLiteral:
-		return I8
-	case *parser.Number:
-		if e.Value == float64(int(e.Value)) {
-			return I32
-		}
-		return FLOAT
-	case *parser.BooleanLiteral:
-		return I1
-	case *parser.Identifier:
-		if info, exists := cg.symbolTable[e.Name]; exists {
-			return info.Type
-		}
-	case *parser.UnaryExpression:
-		return cg.determineType(e.Right)
-	case *parser.BinaryExpression:
-		leftType := cg.determineType(e.Left)
-		rightType := cg.determineType(e.Right)
-		if leftType == FLOAT || rightType == FLOAT {
-			return FLOAT
-		}
-		return leftType
+// typeOf retorna o tipo do valor de uma expressão, como registrado pela
+// análise semântica. Arrays de tamanho fixo são usados como T[]
+func (cg *CodeGenerator) typeOf(expr parser.Expression) types.Type {
+	t := cg.info.TypeOf(expr)
+	if t == nil {
+		// A análise semântica tipa toda expressão de um programa sem erros,
+		// então isto indica um bug nela. O int assumido fica registrado para
+		// que a mesma expressão não seja reportada de novo
+		cg.AddError(fmt.Sprintf("Tipo desconhecido para a expressão '%s'", expr.GetToken().Lexeme), expr.GetToken())
+		cg.info.Types[expr] = types.Int
+		return types.Int
+	}
+	if array, ok := t.(*types.Array); ok && array.IsFixed() {
+		return types.NewSlice(array.Elem)
 	}
-	return I32
+	return t
 }
 
-func (cg *CodeGenerator) llvmTypeFromParserType(t string) Type {
-	switch t {
-	case "int":
-		return I32
-	case "float":
-		return FLOAT
-	case "bool":
-		return I1
-	case "string":
-        return I8
-	default:
-		return I32
+// generateConverted gera a expressão e aplica a conversão registrada pela
+// análise semântica para o contexto em que ela é usada, como um int guardado
+// em uma variável float. Retorna o valor e o seu tipo após a conversão
+func (cg *CodeGenerator) generateConverted(expr parser.Expression) (string, types.Type) {
+	val := cg.generateExpression(expr)
+	valType := cg.typeOf(expr)
+	target, ok := cg.info.Conversions[expr]
+	if !ok {
+		return val, valType
 	}
+	return cg.generateTypeConversion(val, valType, target), target
+}
+
+// resolveType traduz um nome de tipo escrito no código-fonte. Tipos
+// desconhecidos, já reportados pela análise semântica, viram int
+func (cg *CodeGenerator) resolveType(name string) types.Type {
+	resolved, ok := cg.info.Structs.Resolve(name)
+	if !ok {
+		return types.Int
+	}
+	return resolved
+}
+
+// signature retorna o tipo de uma função declarada no programa
+func (cg *CodeGenerator) signature(decl *parser.FunctionDeclaration) *types.Signature {
+	sig := &types.Signature{
+		Params: make([]types.Type, len(decl.Parameters)),
+		Result: cg.resolveType(decl.ReturnType),
+	}
+	for i, param := range decl.Parameters {
+		sig.Params[i] = cg.resolveType(param.Type)
+	}
+	return sig
+}
+
+// llvm retorna o tipo LLVM usado nas instruções para um tipo da linguagem
+func llvm(t types.Type) Type {
+	return Type(t.LLVM())
+}
+
+func isVoid(t types.Type) bool {
+	return types.Identical(t, types.Void)
+}
+
+// emitLoad lê o valor do tipo typ guardado em ptr
+func (cg *CodeGenerator) emitLoad(typ Type, ptr string) string {
+	temp := cg.newTemp()
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+		Op:   "load",
+		Type: typ,
+		Dest: temp,
+		Args: []string{string(typ) + "*", ptr},
+	})
+	return temp
+}
+
+// emitStore guarda o valor do tipo typ em ptr
+func (cg *CodeGenerator) emitStore(value string, typ Type, ptr string) {
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+		Op:   "store",
+		Type: typ,
+		Args: []string{value, string(typ) + "*", ptr},
+	})
 }
 
 func (cg *CodeGenerator) newTemp() string {
@@ -727,12 +1033,19 @@ func (cg *CodeGenerator) newLabel(prefix string) string {
 	cg.labelCounter++
 	return label
 }
-func (cg *CodeGenerator) AddError(msg string) {
-	cg.errors = append(cg.errors, msg)
+
+// AddError registra um erro na posição do token
+func (cg *CodeGenerator) AddError(msg string, tok token.Token) {
+	cg.errors = append(cg.errors, CodegenError{
+		Message: msg,
+		Line:    tok.Line,
+		Column:  tok.Column,
+		Token:   tok.Lexeme,
+	})
 }
 
 // Método para obter erros
-func (cg *CodeGenerator) GetErrors() []string {
+func (cg *CodeGenerator) GetErrors() []CodegenError {
 	return cg.errors
 }
 
@@ -744,112 +1057,513 @@ func (cg *CodeGenerator) generateImplicitMain() {
 	}
 	cg.ir.Functions = append(cg.ir.Functions, mainFn)
 	cg.currentBlock = mainFn.Blocks[0]
+	cg.implicitMain = mainFn
+}
+
+// userSymbolPrefix precede os nomes das funções e variáveis globais do
+// programa no LLVM IR. Identificadores da linguagem não têm '.', então esses
+// nomes nunca colidem com as funções da libc declaradas pelo gerador (abort,
+// calloc, ...) nem com os símbolos internos (@__init_globals, @.str.N)
+const userSymbolPrefix = "gp."
+
+// paramPrefix precede os registradores dos parâmetros (%p.x), que de outro
+// modo poderiam colidir com os temporários (%t0) e os rótulos (%entry)
+// criados pelo gerador
+const paramPrefix = "p."
+
+// llvmFunctionName traduz o nome de uma função da linguagem para o nome
+// usado no LLVM IR. Só um main que retorna int mantém o nome, pois é chamado
+// diretamente pelo C runtime
+func (cg *CodeGenerator) llvmFunctionName(name string) string {
+	if sig, ok := cg.functions[name]; ok && name == "main" && !isVoid(sig.Result) {
+		return name
+	}
+	return userSymbolPrefix + name
 }
 
-func (cg *CodeGenerator) getFunctionReturnType(funcName string) Type {
-	// Verifica nas funções geradas
-	for _, fn := range cg.ir.Functions {
-		if fn.Name == funcName {
-			return fn.ReturnType
+// generateMainWrapper gera o @main que chama o main void do usuário. O C
+// runtime espera que main retorne int, então o wrapper retorna 0
+func (cg *CodeGenerator) generateMainWrapper() {
+	mainFn := &Function{
+		Name:       "main",
+		ReturnType: I32,
+		Blocks:     []*BasicBlock{{Label: "entry"}},
+	}
+	cg.ir.Functions = append(cg.ir.Functions, mainFn)
+	mainFn.Blocks[0].Instructions = append(mainFn.Blocks[0].Instructions, Instruction{
+		Op:   "call",
+		Type: VOID,
+		Args: []string{fmt.Sprintf("void @%s()", cg.llvmFunctionName("main"))},
+	})
+	mainFn.Blocks[0].Terminator = &Instruction{
+		Op:   "ret",
+		Type: I32,
+		Args: []string{"0"},
+	}
+}
+
+// runtimeFunction é uma função da libc usada pelo código gerado
+type runtimeFunction struct {
+	name       string
+	returnType Type
+	params     string // Tipos dos parâmetros, como na declaração LLVM
+}
+
+var runtimeFunctions = []runtimeFunction{
+	{name: "printf", returnType: I32, params: "i8*, ..."},
+	{name: "exit", returnType: VOID, params: "i32"},
+	{name: "calloc", returnType: I8, params: "i64, i64"},
+	{name: "dprintf", returnType: I32, params: "i32, i8*, ..."},
+	{name: "fflush", returnType: I32, params: "i8*"},
+	{name: "abort", returnType: VOID, params: ""},
+	{name: "strcmp", returnType: I32, params: "i8*, i8*"},
+}
+
+// declareRuntime declara as funções da libc no módulo
+func (cg *CodeGenerator) declareRuntime() {
+	for _, fn := range runtimeFunctions {
+		cg.ir.GlobalVars = append(cg.ir.GlobalVars, Instruction{
+			Op:   "declare",
+			Args: []string{fmt.Sprintf("%s @%s(%s)", fn.returnType, fn.name, fn.params)},
+		})
+	}
+}
+
+// callRuntime chama uma função da libc. Os argumentos já vêm com tipo
+// (ex: "i32 %t0"). Retorna o registrador com o resultado, ou "" para
+// funções void
+func (cg *CodeGenerator) callRuntime(name string, args ...string) string {
+	var fn runtimeFunction
+	for _, candidate := range runtimeFunctions {
+		if candidate.name == name {
+			fn = candidate
+		}
+	}
+
+	// Funções variádicas exigem o tipo completo na chamada
+	callee := fmt.Sprintf("%s @%s", fn.returnType, fn.name)
+	if strings.HasSuffix(fn.params, "...") {
+		callee = fmt.Sprintf("%s (%s) @%s", fn.returnType, fn.params, fn.name)
+	}
+	var dest string
+	if fn.returnType != VOID {
+		dest = cg.newTemp()
+	}
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+		Op:   "call",
+		Type: fn.returnType,
+		Dest: dest,
+		Args: []string{fmt.Sprintf("%s(%s)", callee, strings.Join(args, ", "))},
+	})
+	return dest
+}
+
+// generateExitCall encerra o processo com o código informado via exit da libc
+func (cg *CodeGenerator) generateExitCall(call *parser.CallExpression) string {
+	if len(call.Arguments) != 1 {
+		cg.AddError("exit requer exatamente 1 argumento", call.Token)
+		return "0"
+	}
+
+	code := cg.generateExpression(call.Arguments[0])
+	code = cg.generateTypeConversion(code, cg.typeOf(call.Arguments[0]), types.Int)
+	cg.callRuntime("exit", fmt.Sprintf("i32 %s", code))
+	cg.currentBlock.Terminator = &Instruction{Op: "unreachable"}
+	cg.startUnreachableBlock("exit.after")
+
+	return "0"
+}
+
+// generatePrintCall escreve os argumentos separados por espaço e termina a
+// linha, montando o formato do printf a partir dos tipos dos argumentos
+func (cg *CodeGenerator) generatePrintCall(call *parser.CallExpression) {
+	directives := make([]string, len(call.Arguments))
+	args := make([]string, len(call.Arguments))
+	for i, argExpr := range call.Arguments {
+		var verb byte
+		argType := cg.typeOf(argExpr)
+		if basic, ok := argType.(*types.Basic); ok {
+			switch basic.Kind {
+			case types.KindInt:
+				verb = 'd'
+			case types.KindFloat:
+				verb = 'f'
+			case types.KindString:
+				verb = 's'
+			case types.KindBool:
+				verb = 't'
+			}
+		}
+		if verb == 0 {
+			cg.AddError(fmt.Sprintf("Tipo não suportado para print: %s", argType), argExpr.GetToken())
+			return
 		}
+		directives[i] = "%" + string(verb)
+		args[i] = cg.printfArg(argExpr, verb)
 	}
 
-	// Funções padrão (como print, etc) em breve, maybeee
-	switch funcName {
-	case "":
-		return I32
-	// Adicione outros casos conforme necessário
+	// O printf da libc não conhece %t; printfArg passa booleanos como string
+	cg.callPrintf(strings.ReplaceAll(strings.Join(directives, " "), "%t", "%s")+"\n", args)
+}
+
+// generatePrintfCall traduz o builtin printf para o printf da libc. O formato
+// já foi validado pela análise semântica
+func (cg *CodeGenerator) generatePrintfCall(call *parser.CallExpression) {
+	literal, ok := call.Arguments[0].(*parser.StringLiteral)
+	if !ok {
+		cg.AddError("O formato de printf deve ser uma string literal", call.Arguments[0].GetToken())
+		return
+	}
+	directives, err := format.Parse(literal.Value)
+	if err != nil || len(directives) != len(call.Arguments)-1 {
+		cg.AddError(fmt.Sprintf("Formato de printf inválido: %q", literal.Value), literal.Token)
+		return
+	}
+
+	args := make([]string, len(directives))
+	for i, directive := range directives {
+		args[i] = cg.printfArg(call.Arguments[i+1], directive.Verb)
+	}
+	cg.callPrintf(format.ToC(literal.Value, directives), args)
+}
+
+// printfArg gera um argumento do printf no tipo esperado pela diretiva. Como
+// o printf é variádico, floats são promovidos a double e booleanos viram as
+// strings "true" ou "false"
+func (cg *CodeGenerator) printfArg(expr parser.Expression, verb byte) string {
+	// Um int passado para %f chega convertido para float
+	value, _ := cg.generateConverted(expr)
+
+	switch verb {
+	case 'd':
+		return fmt.Sprintf("i32 %s", value)
+	case 'f':
+		return fmt.Sprintf("double %s", cg.emitCast("fpext", value, FLOAT, DOUBLE))
+	case 't':
+		trueName, trueLen := cg.ir.InternString("true")
+		falseName, falseLen := cg.ir.InternString("false")
+		temp := cg.newTemp()
+		cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+			Op:   "select",
+			Type: I1,
+			Dest: temp,
+			Args: []string{value, stringPointer(trueName, trueLen), stringPointer(falseName, falseLen)},
+		})
+		return fmt.Sprintf("i8* %s", temp)
 	default:
-		return I32 // Padrão para funções desconhecidas
+		return fmt.Sprintf("i8* %s", cg.nonNullString(value))
 	}
 }
 
-func (cg *CodeGenerator) addPrintfSupport() {
-	cg.ir.GlobalVars = append(cg.ir.GlobalVars, Instruction{
-		Op:   "declare",
-		Args: []string{"i32 @printf(i8*, ...)"},
+// nonNullString troca uma string nula por "". Strings em arrays e structs
+// recém-criados começam zeradas (null), o que a libc não aceita
+func (cg *CodeGenerator) nonNullString(value string) string {
+	isNull := cg.newTemp()
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+		Op:   "icmp",
+		Dest: isNull,
+		Args: []string{"eq", string(I8), value, "null"},
 	})
+	temp := cg.newTemp()
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+		Op:   "select",
+		Type: I1,
+		Dest: temp,
+		Args: []string{isNull, stringPointer(cg.ir.InternString("")), "i8* " + value},
+	})
+	return temp
+}
 
-	// Formato para inteiros
-	cg.ir.GlobalVars = append(cg.ir.GlobalVars, Instruction{
-		Op:   "@.str.int",
-		Args: []string{"= private unnamed_addr constant [4 x i8] c\"%d\\0A\\00\", align 1"},
+// callPrintf chama o printf da libc com um formato constante
+func (cg *CodeGenerator) callPrintf(formatValue string, args []string) {
+	name, length := cg.ir.InternString(formatValue)
+	cg.callRuntime("printf", append([]string{stringPointer(name, length)}, args...)...)
+}
+
+// stringPointer retorna o ponteiro i8* para o início de uma constante string
+func stringPointer(name string, length int) string {
+	return "i8* " + stringConstant(name, length)
+}
+
+// stringConstant é o endereço de uma constante string, sem o tipo na frente,
+// como usado em inicializadores
+func stringConstant(name string, length int) string {
+	return fmt.Sprintf("getelementptr inbounds ([%d x i8], [%d x i8]* %s, i32 0, i32 0)",
+		length, length, name)
+}
+
+func (cg *CodeGenerator) generateStringLiteral(str *parser.StringLiteral) string {
+	strName, strLen := cg.ir.InternString(str.Value)
+
+	temp := cg.newTemp()
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+		Op:   "getelementptr",
+		Dest: temp,
+		Args: []string{fmt.Sprintf("[%d x i8], [%d x i8]* %s, i32 0, i32 0", strLen, strLen, strName)},
 	})
 
-	// Formato para floats
-	cg.ir.GlobalVars = append(cg.ir.GlobalVars, Instruction{
-		Op:   "@.str.float",
-		Args: []string{"= private unnamed_addr constant [4 x i8] c\"%f\\0A\\00\", align 1"},
+	return temp
+}
+
+// arraySlice monta o T[] que aponta para o array de tamanho fixo guardado
+// em array
+func (cg *CodeGenerator) arraySlice(array string, arrayType *types.Array) string {
+	data := cg.newTemp()
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+		Op:   "getelementptr inbounds",
+		Type: llvm(arrayType),
+		Dest: data,
+		Args: []string{fmt.Sprintf("%s* %s", llvm(arrayType), array), "i32 0", "i32 0"},
+	})
+	return cg.buildSlice(strconv.Itoa(arrayType.Len), data, arrayType.Elem)
+}
+
+// buildSlice monta um T[] a partir do tamanho e do ponteiro para os elementos
+func (cg *CodeGenerator) buildSlice(length, data string, elem types.Type) string {
+	slice := llvm(types.NewSlice(elem))
+	withLength := cg.newTemp()
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+		Op:   "insertvalue",
+		Type: slice,
+		Dest: withLength,
+		Args: []string{"undef", "i32 " + length, "0"},
 	})
+	result := cg.newTemp()
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+		Op:   "insertvalue",
+		Type: slice,
+		Dest: result,
+		Args: []string{withLength, fmt.Sprintf("%s* %s", llvm(elem), data), "1"},
+	})
+	return result
+}
 
-	// Formato para strings
-	cg.ir.GlobalVars = append(cg.ir.GlobalVars, Instruction{
-		Op:   "@.str.str",
-		Args: []string{"= private unnamed_addr constant [4 x i8] c\"%s\\0A\\00\", align 1"},
+// sliceField lê o tamanho (campo 0) ou o ponteiro para os elementos (campo 1)
+// de um T[]
+func (cg *CodeGenerator) sliceField(slice string, sliceType types.Type, field int) string {
+	temp := cg.newTemp()
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+		Op:   "extractvalue",
+		Type: llvm(sliceType),
+		Dest: temp,
+		Args: []string{slice, strconv.Itoa(field)},
 	})
+	return temp
 }
-func (cg *CodeGenerator) generatePrintCall(call *parser.CallExpression) {
-    if len(call.Arguments) != 1 {
-        cg.AddError("print requer exatamente 1 argumento")
-        return
-    }
-    
-    arg := cg.generateExpression(call.Arguments[0])
-    argType := cg.determineType(call.Arguments[0])
-    
-    var formatStr string
-    var argTypeStr string
-    
-    switch argType {
-    case I32:
-        formatStr = "@.str.int"
-        argTypeStr = "i32"
-    case FLOAT:
-        formatStr = "@.str.float"
-        argTypeStr = "float"
-    case "i8*":
-        formatStr = "@.str.str"
-        argTypeStr = "i8*"
-    default:
-        cg.AddError(fmt.Sprintf("Tipo não suportado para print: %s", argType))
-        return
-    }
-    
-    fmtPtr := cg.newTemp()
-    cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
-        Op:   "getelementptr",
-        Dest: fmtPtr,
-        Args: []string{fmt.Sprintf("[4 x i8], [4 x i8]* %s, i32 0, i32 0", formatStr)},
-    })
-    
-    // Adiciona a chamada ao printf com uma variável temporária para o resultado
-    callResult := cg.newTemp()
-    cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
-        Op:   "call",
-        Dest: callResult,
-        Type: I32,
-        Args: []string{fmt.Sprintf("i32 (i8*, ...) @printf(i8* %s, %s %s)", fmtPtr, argTypeStr, arg)},
-    })
+
+// generateNewArray aloca no heap um T[] com todos os elementos zerados
+func (cg *CodeGenerator) generateNewArray(expr *parser.NewArrayExpression) string {
+	elem := cg.resolveType(expr.ElementType)
+	length := cg.generateExpression(expr.Size)
+
+	notNegative := cg.newTemp()
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+		Op:   "icmp",
+		Type: I1,
+		Dest: notNegative,
+		Args: []string{"sge", string(I32), length, "0"},
+	})
+	cg.generateRuntimeCheck(notNegative, expr.Token.Line,
+		"tamanho de array negativo (%d)", "i32 "+length)
+
+	count := cg.newTemp()
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+		Op:   "sext",
+		Type: "i64",
+		Dest: count,
+		Args: []string{string(I32), length},
+	})
+	// Tamanho do elemento calculado pelo próprio LLVM, sem depender da plataforma
+	elemType := llvm(elem)
+	elemSize := fmt.Sprintf("i64 ptrtoint (%s* getelementptr (%s, %s* null, i32 1) to i64)",
+		elemType, elemType, elemType)
+	memory := cg.callRuntime("calloc", "i64 "+count, elemSize)
+
+	data := cg.emitCast("bitcast", memory, I8, elemType+"*")
+	return cg.buildSlice(length, data, elem)
 }
 
-func (cg *CodeGenerator) generateStringLiteral(str *parser.StringLiteral) string {
-    strName := fmt.Sprintf("@.str.%d", cg.stringCounter)
-    cg.stringCounter++
-    
-    strValue := str.Value + "\\00"
-    strLen := len(str.Value) + 1
-    
-    cg.ir.GlobalVars = append(cg.ir.GlobalVars, Instruction{
-        Op:    strName,
-        Args:  []string{fmt.Sprintf("= private unnamed_addr constant [%d x i8] c\"%s\", align 1", strLen, strValue)},
-    })
-    
-    temp := cg.newTemp()
-    cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
-        Op:   "getelementptr",
-        Dest: temp,
-        Args: []string{fmt.Sprintf("[%d x i8], [%d x i8]* %s, i32 0, i32 0", strLen, strLen, strName)},
-    })
-    
-    return temp
-}
\ No newline at end of file
+// elementPointer calcula o endereço de xs[i], abortando o programa quando o
+// índice está fora dos limites do array
+func (cg *CodeGenerator) elementPointer(expr *parser.IndexExpression) (string, types.Type) {
+	sliceType, ok := cg.typeOf(expr.Array).(*types.Array)
+	if !ok {
+		cg.AddError(fmt.Sprintf("'%s' não é um array", expr.Array.GetToken().Lexeme), expr.Token)
+		return "null", types.Int
+	}
+	elem := llvm(sliceType.Elem)
+	slice := cg.generateExpression(expr.Array)
+	index := cg.generateExpression(expr.Index)
+
+	length := cg.sliceField(slice, sliceType, 0)
+	data := cg.sliceField(slice, sliceType, 1)
+
+	// Na comparação sem sinal, índices negativos também ficam fora dos limites
+	inBounds := cg.newTemp()
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+		Op:   "icmp",
+		Type: I1,
+		Dest: inBounds,
+		Args: []string{"ult", string(I32), index, length},
+	})
+	cg.generateRuntimeCheck(inBounds, expr.Token.Line,
+		"índice %d fora dos limites do array de tamanho %d", "i32 "+index, "i32 "+length)
+
+	ptr := cg.newTemp()
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+		Op:   "getelementptr inbounds",
+		Type: elem,
+		Dest: ptr,
+		Args: []string{fmt.Sprintf("%s* %s", elem, data), "i32 " + index},
+	})
+	return ptr, sliceType.Elem
+}
+
+func (cg *CodeGenerator) generateIndexExpr(expr *parser.IndexExpression) string {
+	ptr, elem := cg.elementPointer(expr)
+	return cg.emitLoad(llvm(elem), ptr)
+}
+
+func (cg *CodeGenerator) generateIndexAssignment(assign *parser.IndexAssignmentStatement) {
+	ptr, elem := cg.elementPointer(assign.Target)
+	val, _ := cg.generateConverted(assign.Value)
+	cg.emitStore(val, llvm(elem), ptr)
+}
+
+// generateLenCall retorna a quantidade de elementos de um array
+func (cg *CodeGenerator) generateLenCall(call *parser.CallExpression) string {
+	if len(call.Arguments) != 1 {
+		cg.AddError("len requer exatamente 1 argumento", call.Token)
+		return "0"
+	}
+	arg := call.Arguments[0]
+	return cg.sliceField(cg.generateExpression(arg), cg.typeOf(arg), 0)
+}
+
+// generateRuntimeCheck segue adiante quando ok é verdadeiro; caso contrário
+// escreve no stderr o erro com a linha do código-fonte e aborta o programa.
+// message é um formato do printf da libc para os argumentos args
+func (cg *CodeGenerator) generateRuntimeCheck(ok string, line int, message string, args ...string) {
+	okLabel := cg.newLabel("check.ok")
+	failLabel := cg.newLabel("check.fail")
+	cg.currentBlock.Terminator = &Instruction{
+		Op:   "br",
+		Args: []string{ok, okLabel, failLabel},
+	}
+
+	failBlock := &BasicBlock{Label: failLabel}
+	cg.ir.CurrentFunction().Blocks = append(cg.ir.CurrentFunction().Blocks, failBlock)
+	cg.currentBlock = failBlock
+	// Escreve o que o programa já imprimiu antes da mensagem de erro
+	cg.callRuntime("fflush", "i8* null")
+	name, length := cg.ir.InternString("erro na linha %d: " + message + "\n")
+	cg.callRuntime("dprintf", append([]string{"i32 2", stringPointer(name, length),
+		fmt.Sprintf("i32 %d", line)}, args...)...)
+	cg.callRuntime("abort")
+	failBlock.Terminator = &Instruction{Op: "unreachable"}
+
+	okBlock := &BasicBlock{Label: okLabel}
+	cg.ir.CurrentFunction().Blocks = append(cg.ir.CurrentFunction().Blocks, okBlock)
+	cg.currentBlock = okBlock
+}
+
+// declareStructs define um tipo LLVM nomeado para cada struct de nível
+// superior
+func (cg *CodeGenerator) declareStructs() {
+	for _, named := range cg.info.Structs.Structs() {
+		cg.ir.GlobalVars = append(cg.ir.GlobalVars, Instruction{
+			Op:   named.LLVM(),
+			Args: []string{"= type " + named.LLVMBody()},
+		})
+	}
+}
+
+// addressOf retorna o endereço e o tipo de uma expressão que designa uma
+// variável, um elemento de array ou um campo de struct
+func (cg *CodeGenerator) addressOf(expr parser.Expression) (string, types.Type, bool) {
+	switch e := expr.(type) {
+	case *parser.Identifier:
+		if info, exists := cg.symbolTable.Resolve(e.Name); exists {
+			return info.Alloca, info.Type, true
+		}
+	case *parser.IndexExpression:
+		ptr, elem := cg.elementPointer(e)
+		return ptr, elem, true
+	case *parser.MemberExpression:
+		ptr, fieldType := cg.fieldPointer(e)
+		return ptr, fieldType, true
+	}
+	return "", nil, false
+}
+
+// fieldPointer calcula o endereço de p.x com getelementptr
+func (cg *CodeGenerator) fieldPointer(expr *parser.MemberExpression) (string, types.Type) {
+	base, baseType, ok := cg.addressOf(expr.Object)
+	if !ok {
+		// Valores temporários (ex: o retorno de uma função) ganham uma
+		// variável própria para que o campo tenha um endereço
+		baseType = cg.typeOf(expr.Object)
+		value := cg.generateExpression(expr.Object)
+		base = cg.emitAlloca(llvm(baseType))
+		cg.emitStore(value, llvm(baseType), base)
+	}
+
+	var field *types.Field
+	index := -1
+	if named, ok := baseType.(*types.Named); ok {
+		field, index = named.Field(expr.Field)
+	}
+	if field == nil {
+		cg.AddError(fmt.Sprintf("Campo '%s' desconhecido em %s", expr.Field, baseType), expr.Token)
+		return "null", types.Int
+	}
+
+	ptr := cg.newTemp()
+	cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+		Op:   "getelementptr inbounds",
+		Type: llvm(baseType),
+		Dest: ptr,
+		Args: []string{fmt.Sprintf("%s* %s", llvm(baseType), base), "i32 0", fmt.Sprintf("i32 %d", index)},
+	})
+	return ptr, field.Type
+}
+
+func (cg *CodeGenerator) generateMemberExpr(expr *parser.MemberExpression) string {
+	ptr, fieldType := cg.fieldPointer(expr)
+	return cg.emitLoad(llvm(fieldType), ptr)
+}
+
+func (cg *CodeGenerator) generateFieldAssignment(assign *parser.FieldAssignmentStatement) {
+	ptr, fieldType := cg.fieldPointer(assign.Target)
+	val, _ := cg.generateConverted(assign.Value)
+	cg.emitStore(val, llvm(fieldType), ptr)
+}
+
+// generateStructLiteral monta o valor da struct campo a campo, partindo de
+// uma struct zerada
+func (cg *CodeGenerator) generateStructLiteral(lit *parser.StructLiteral) string {
+	named, ok := cg.info.Structs.Lookup(lit.TypeName)
+	if !ok {
+		cg.AddError(fmt.Sprintf("Struct '%s' não declarada", lit.TypeName), lit.Token)
+		return "zeroinitializer"
+	}
+	result := "zeroinitializer"
+	for _, fv := range lit.Fields {
+		field, index := named.Field(fv.Name)
+		if field == nil {
+			cg.AddError(fmt.Sprintf("Campo '%s' desconhecido em %s", fv.Name, lit.TypeName), fv.Token)
+			continue
+		}
+		val, _ := cg.generateConverted(fv.Value)
+
+		temp := cg.newTemp()
+		cg.currentBlock.Instructions = append(cg.currentBlock.Instructions, Instruction{
+			Op:   "insertvalue",
+			Type: llvm(named),
+			Dest: temp,
+			Args: []string{result, fmt.Sprintf("%s %s", llvm(field.Type), val), strconv.Itoa(index)},
+		})
+		result = temp
+	}
+	return result
+}
@@ -0,0 +1,537 @@
+package intermediatecodegeneration
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"simple-compiler/lexer"
+	"simple-compiler/parser"
+	"simple-compiler/semantic"
+	"strings"
+	"testing"
+)
+
+// generate passa o código por todas as fases até o LLVM IR, falhando o teste
+// em qualquer erro
+func generate(t *testing.T, src string) string {
+	t.Helper()
+
+	p := parser.New(lexer.Tokenize(src))
+	statements := p.Parse()
+	if len(p.Errors) > 0 {
+		t.Fatalf("erros de sintaxe: %v", p.Errors)
+	}
+
+	analyzer := semantic.New(statements)
+	if errs := analyzer.Analyze(); len(errs) > 0 {
+		t.Fatalf("erros semânticos: %v", errs)
+	}
+
+	generator := NewCodeGenerator(analyzer.Info())
+	ir := generator.GenerateFromAST(statements)
+	if errs := generator.GetErrors(); len(errs) > 0 {
+		t.Fatalf("erros na geração de código: %v", errs)
+	}
+	return ir.GenerateLLVM()
+}
+
+func requireTool(t *testing.T, name string) {
+	t.Helper()
+	if _, err := exec.LookPath(name); err != nil {
+		t.Skipf("%s não encontrado no PATH", name)
+	}
+}
+
+// verifyIR confere o IR com o verificador do LLVM
+func verifyIR(t *testing.T, ir string) {
+	t.Helper()
+	requireTool(t, "opt")
+	cmd := exec.Command("opt", "-verify", "-disable-output")
+	cmd.Stdin = strings.NewReader(ir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("IR inválido: %v\n%s\n%s", err, out, ir)
+	}
+}
+
+// runIR compila o IR com llc e cc e executa o binário, retornando a saída
+// padrão, a saída de erro e o código de saída
+func runIR(t *testing.T, ir string) (stdout, stderr string, exitCode int) {
+	t.Helper()
+	requireTool(t, "llc")
+	requireTool(t, "cc")
+
+	dir := t.TempDir()
+	llFile := filepath.Join(dir, "prog.ll")
+	objFile := filepath.Join(dir, "prog.o")
+	binFile := filepath.Join(dir, "prog")
+	if err := os.WriteFile(llFile, []byte(ir), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := [][]string{
+		{"llc", "-filetype=obj", "-relocation-model=pic", llFile, "-o", objFile},
+		{"cc", objFile, "-o", binFile},
+	}
+	for _, step := range steps {
+		if out, err := exec.Command(step[0], step[1:]...).CombinedOutput(); err != nil {
+			t.Fatalf("%s falhou: %v\n%s", step[0], err, out)
+		}
+	}
+
+	var outBuf, errBuf bytes.Buffer
+	cmd := exec.Command(binFile)
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	} else if err != nil {
+		t.Fatalf("falha ao executar o programa: %v", err)
+	}
+	return outBuf.String(), errBuf.String(), exitCode
+}
+
+// compileAndRun gera, verifica e executa o programa
+func compileAndRun(t *testing.T, src string) (stdout, stderr string, exitCode int) {
+	t.Helper()
+	ir := generate(t, src)
+	verifyIR(t, ir)
+	return runIR(t, ir)
+}
+
+func TestGenerateArrays(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "array fixo local",
+			src: `int[4] xs
+for (int i = 0; i < 4; i = i + 1) {
+	xs[i] = i * 10
+}
+print(xs[3], len(xs))
+`,
+			want: "30 4\n",
+		},
+		{
+			name: "array dinâmico como parâmetro e retorno",
+			src: `func squares(int n) int[] {
+	int[] out = new int[n]
+	for (int i = 0; i < n; i = i + 1) {
+		out[i] = i * i
+	}
+	return out
+}
+
+func sum(int[] xs) int {
+	int total = 0
+	for (int i = 0; i < len(xs); i = i + 1) {
+		total = total + xs[i]
+	}
+	return total
+}
+
+func main() void {
+	int[] ys = squares(5)
+	print(ys[4], len(ys), sum(ys))
+}
+`,
+			want: "16 5 30\n",
+		},
+		{
+			name: "valores iniciais",
+			src: `float[] fs = new float[2]
+bool[2] flags
+string[] names = new string[1]
+fs[1] = 2
+print(fs[0], fs[1], flags[0])
+print(names[0])
+`,
+			want: "0.000000 2.000000 false\n\n",
+		},
+		{
+			name: "array global",
+			src: `int[3] global
+func set() void {
+	global[2] = 7
+}
+set()
+print(global[2])
+`,
+			want: "7\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, code := compileAndRun(t, tt.src)
+			if code != 0 {
+				t.Fatalf("código de saída %d: %s", code, stderr)
+			}
+			if stdout != tt.want {
+				t.Errorf("saída %q, esperava %q", stdout, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateArrayForaDosLimites(t *testing.T) {
+	src := `int[] xs = new int[3]
+xs[1] = 5
+print(xs[1])
+print(xs[3])
+print("não chega aqui")
+`
+	stdout, stderr, code := compileAndRun(t, src)
+	if code == 0 {
+		t.Fatalf("esperava código de saída diferente de zero")
+	}
+	if stdout != "5\n" {
+		t.Errorf("saída %q, esperava %q", stdout, "5\n")
+	}
+	if !strings.Contains(stderr, "índice 3 fora dos limites") {
+		t.Errorf("mensagem de erro inesperada: %q", stderr)
+	}
+}
+
+// Funções e globais do usuário não podem colidir com as funções do runtime
+func TestGenerateNomesDoRuntime(t *testing.T) {
+	src := `int calloc = 2
+func abort() void {
+	print("abort do usuário")
+}
+func fflush(int x) int {
+	return x + calloc
+}
+func main() void {
+	abort()
+	int[] xs = new int[2]
+	print(fflush(4), len(xs))
+}
+`
+	stdout, stderr, code := compileAndRun(t, src)
+	if code != 0 {
+		t.Fatalf("código de saída %d: %s", code, stderr)
+	}
+	want := "abort do usuário\n6 2\n"
+	if stdout != want {
+		t.Errorf("saída %q, esperava %q", stdout, want)
+	}
+}
+
+// Strings são comparadas pelo conteúdo, não pelo endereço
+func TestGenerateComparacaoDeStrings(t *testing.T) {
+	src := `func identity(string a) string {
+	return a
+}
+string s = identity("abc")
+string vazia
+print(s == "abc", s != "abc", s == "abd")
+print(vazia == "", "" == "x", true == false, true != false)
+`
+	// Constantes iguais compartilham o endereço, então o resultado sozinho não
+	// mostra que o conteúdo foi comparado
+	if ir := generate(t, src); !strings.Contains(ir, "call i32 @strcmp(") {
+		t.Errorf("esperava que a comparação usasse strcmp:\n%s", ir)
+	}
+
+	stdout, stderr, code := compileAndRun(t, src)
+	if code != 0 {
+		t.Fatalf("código de saída %d: %s", code, stderr)
+	}
+	want := "true false false\ntrue false false true\n"
+	if stdout != want {
+		t.Errorf("saída %q, esperava %q", stdout, want)
+	}
+}
+
+func TestGenerateConversoes(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "divisão com literal float",
+			src:  "float f = 5.0 / 2\nprint(f)\n",
+			want: "2.500000\n",
+		},
+		{
+			name: "literal com ponto e valor inteiro",
+			src:  "float f = 2.0\nprint(f / 4)\n",
+			want: "0.500000\n",
+		},
+		{
+			name: "divisão inteira",
+			src:  "int n = 5\nprint(n / 2, float(n) / 2)\n",
+			want: "2 2.500000\n",
+		},
+		{
+			name: "int(...) trunca em direção a zero",
+			src:  "float f = -2.7\nprint(int(f), int(2.7))\n",
+			want: "-2 2\n",
+		},
+		{
+			name: "int para float implícito",
+			src: `func half(float x) float {
+	return x / 2
+}
+int n = 3
+float dx = n * 2
+dx = dx + n
+print(dx, half(n))
+`,
+			want: "9.000000 1.500000\n",
+		},
+		{
+			name: "limites do int",
+			src:  "int menor = -2147483648\nfunc f() int {\n\treturn -2147483648 + 2147483647\n}\nprint(menor, f())\n",
+			want: "-2147483648 -1\n",
+		},
+		{
+			name: "comparação entre int e float",
+			src:  "int n = 2\nprint(n < 2.5, n == 2.0)\n",
+			want: "true true\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, code := compileAndRun(t, tt.src)
+			if code != 0 {
+				t.Fatalf("código de saída %d: %s", code, stderr)
+			}
+			if stdout != tt.want {
+				t.Errorf("saída %q, esperava %q", stdout, tt.want)
+			}
+		})
+	}
+}
+
+// Cada execução de uma declaração sem inicializador começa do valor zero, e
+// comandos depois de um return continuam gerando IR válido
+func TestGenerateEscopos(t *testing.T) {
+	src := `func f(int n) int {
+	int total = 0
+	for (int i = 0; i < n; i = i + 1) {
+		int x
+		string s
+		x = x + i
+		total = total + x
+		print(s, x)
+		s = "usado"
+	}
+	return total
+	print("nunca")
+}
+int x = 10
+{
+	int x = 1
+	print(x)
+}
+print(x, f(3))
+`
+	stdout, stderr, code := compileAndRun(t, src)
+	if code != 0 {
+		t.Fatalf("código de saída %d: %s", code, stderr)
+	}
+	want := "1\n 0\n 1\n 2\n10 3\n"
+	if stdout != want {
+		t.Errorf("saída %q, esperava %q", stdout, want)
+	}
+}
+
+// Uma variável pode ter o nome de um builtin sem impedir o uso dele
+func TestGenerateVariavelComNomeDeBuiltin(t *testing.T) {
+	src := `int len = 3
+func f(int[] xs) int {
+	int len = len(xs)
+	return len
+}
+int[] xs = new int[2]
+print(len, len(xs), f(xs))
+`
+	stdout, stderr, code := compileAndRun(t, src)
+	if code != 0 {
+		t.Fatalf("código de saída %d: %s", code, stderr)
+	}
+	if want := "3 2 2\n"; stdout != want {
+		t.Errorf("saída %q, esperava %q", stdout, want)
+	}
+}
+
+// Parâmetros não podem colidir com os temporários e rótulos do gerador
+func TestGenerateNomesDeParametros(t *testing.T) {
+	src := `func f(int t0, int entry, float t1) float {
+	int x = t0 + entry
+	return x + t1
+}
+print(f(1, 2, 0.5))
+`
+	stdout, stderr, code := compileAndRun(t, src)
+	if code != 0 {
+		t.Fatalf("código de saída %d: %s", code, stderr)
+	}
+	if want := "3.500000\n"; stdout != want {
+		t.Errorf("saída %q, esperava %q", stdout, want)
+	}
+}
+
+// Arrays criados com new sobrevivem à função que os criou
+func TestGenerateArrayRetornado(t *testing.T) {
+	src := `int[] g
+func f() int[] {
+	int[] xs = new int[3]
+	xs[0] = 42
+	g = xs
+	return xs
+}
+int[] ys = f()
+print(ys[0], g[0])
+`
+	stdout, stderr, code := compileAndRun(t, src)
+	if code != 0 {
+		t.Fatalf("código de saída %d: %s", code, stderr)
+	}
+	if want := "42 42\n"; stdout != want {
+		t.Errorf("saída %q, esperava %q", stdout, want)
+	}
+}
+
+// Structs são valores: atribuições e parâmetros recebem uma cópia dos campos
+func TestGenerateStructs(t *testing.T) {
+	src := `struct Point {
+	int x
+	float y
+}
+
+struct Line {
+	Point from
+	Point to
+	string name
+	int[] marks
+}
+
+func move(Point p, int dx) Point {
+	p.x = p.x + dx
+	return p
+}
+
+func origin() Point {
+	return Point{}
+}
+
+Line global
+
+func main() void {
+	Point a = Point{x: 1, y: 2}
+	Point b = a
+	b.x = 10
+	Point c = move(a, 5)
+	print(a.x, a.y, b.x, c.x)
+	print(origin().x, move(c, 1).x)
+
+	Line l = Line{from: a, name: "diagonal", marks: new int[2]}
+	l.to.y = 4.5
+	l.marks[1] = 7
+	print(l.name, l.from.x, l.to.y, l.marks[1], len(l.marks))
+
+	global.from.x = 3
+	print(global.from.x, global.name, len(global.marks))
+}
+`
+	stdout, stderr, code := compileAndRun(t, src)
+	if code != 0 {
+		t.Fatalf("código de saída %d: %s", code, stderr)
+	}
+	want := "1 2.000000 10 6\n0 7\ndiagonal 1 4.500000 7 2\n3  0\n"
+	if stdout != want {
+		t.Errorf("saída %q, esperava %q", stdout, want)
+	}
+}
+
+// continue no for executa a atualização antes de testar a condição, e break
+// só encerra o laço mais interno
+func TestGenerateBreakEContinue(t *testing.T) {
+	src := `for (int i = 0; i < 6; i = i + 1) {
+	if (i == 1) {
+		continue
+	}
+	if (i == 4) {
+		break
+	}
+	print(i)
+}
+int n = 0
+while (true) {
+	n = n + 1
+	if (n < 3) {
+		continue
+	}
+	for (;;) {
+		break
+		print("nunca")
+	}
+	break
+}
+print(n)
+`
+	stdout, stderr, code := compileAndRun(t, src)
+	if code != 0 {
+		t.Fatalf("código de saída %d: %s", code, stderr)
+	}
+	if want := "0\n2\n3\n3\n"; stdout != want {
+		t.Errorf("saída %q, esperava %q", stdout, want)
+	}
+}
+
+func TestGeneratePrint(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "print separa os valores por espaço",
+			src:  "bool flag = 1 < 2\nprint(1, 2.5, \"texto\", flag, !flag)\nprint()\n",
+			want: "1 2.500000 texto true false\n\n",
+		},
+		{
+			name: "print não interpreta % nas strings",
+			src:  "print(\"100%\", \"%d\")\n",
+			want: "100% %d\n",
+		},
+		{
+			name: "printf com diretivas",
+			src:  "printf(\"x=%d y=%.1f s=%s b=%t\\n\", 3, 2.25, \"ok\", true)\n",
+			want: "x=3 y=2.2 s=ok b=true\n",
+		},
+		{
+			name: "printf com largura, %% e int em %f",
+			src:  "printf(\"[%5d|%-6t|%.2f] 100%%\\n\", 42, false, 1)\n",
+			want: "[   42|false |1.00] 100%\n",
+		},
+		{
+			name: "string zerada de um array",
+			src:  "string[] names = new string[1]\nprintf(\"[%s]\\n\", names[0])\nprint(names[0], 1)\n",
+			want: "[]\n 1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, code := compileAndRun(t, tt.src)
+			if code != 0 {
+				t.Fatalf("código de saída %d: %s", code, stderr)
+			}
+			if stdout != tt.want {
+				t.Errorf("saída %q, esperava %q", stdout, tt.want)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ type Type string
 const (
 	I32   Type = "i32"
 	FLOAT Type = "float"
+	DOUBLE Type = "double" // Só usado nos argumentos variádicos do printf
 	I1    Type = "i1"
 	VOID  Type = "void"
 	I8 Type = "i8*"
@@ -47,6 +48,7 @@ type IntermediateRep struct {
 	GlobalVars   []Instruction
 	TempCounter  int
 	BlockCounter int
+	strings      map[string]string // Conteúdo -> nome das constantes string já criadas
 }
 
 func NewIR() *IntermediateRep {
@@ -55,9 +57,45 @@ func NewIR() *IntermediateRep {
 		GlobalVars:   []Instruction{},
 		TempCounter:  0,
 		BlockCounter: 0,
+		strings:      make(map[string]string),
 	}
 }
 
+// InternString retorna a constante global com o conteúdo da string, criando-a
+// na primeira vez; strings iguais compartilham a mesma constante. Retorna o
+// nome e o tamanho do array em bytes, incluindo o terminador
+func (ir *IntermediateRep) InternString(value string) (string, int) {
+	length := len(value) + 1 // Em bytes, já que value está em UTF-8
+	if name, ok := ir.strings[value]; ok {
+		return name, length
+	}
+
+	name := fmt.Sprintf("@.str.%d", len(ir.strings))
+	ir.strings[value] = name
+	ir.GlobalVars = append(ir.GlobalVars, Instruction{
+		Op:   name,
+		Args: []string{fmt.Sprintf("= private unnamed_addr constant [%d x i8] c\"%s\\00\", align 1",
+			length, llvmStringLiteral(value))},
+	})
+	return name, length
+}
+
+// llvmStringLiteral escreve os bytes de uma string no formato c"..." do LLVM:
+// caracteres ASCII imprimíveis ficam como estão e os demais (incluindo aspas,
+// barras e bytes de caracteres UTF-8) viram escapes \HH
+func llvmStringLiteral(value string) string {
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		b := value[i]
+		if b >= 0x20 && b < 0x7f && b != '"' && b != '\\' {
+			sb.WriteByte(b)
+		} else {
+			fmt.Fprintf(&sb, "\\%02X", b)
+		}
+	}
+	return sb.String()
+}
+
 func (ir *IntermediateRep) NewTemp() string {
 	temp := fmt.Sprintf("%%t%d", ir.TempCounter)
 	ir.TempCounter++
@@ -134,6 +172,9 @@ func (i Instruction) Format() string {
 		return fmt.Sprintf("declare %s", i.Args[0])
 	case "call":
 		if len(i.Args) > 0 {
+			if i.Dest == "" {
+				return fmt.Sprintf("%s %s", i.Op, i.Args[0])
+			}
 			return fmt.Sprintf("%s = %s %s", i.Dest, i.Op, i.Args[0])
 		}
 		return fmt.Sprintf("%s %s", i.Op, i.Type)
@@ -144,6 +185,11 @@ func (i Instruction) Format() string {
 		// Formato: %dest = icmp <predicate> <type> <op1>, <op2>
 		return fmt.Sprintf("%s = %s %s %s %s, %s",
 			i.Dest, i.Op, i.Args[0], i.Args[1], i.Args[2], i.Args[3])
+	case "sitofp", "fptosi", "fpext", "sext", "bitcast":
+		return fmt.Sprintf("%s = %s %s %s to %s", i.Dest, i.Op, i.Args[0], i.Args[1], i.Type)
+	case "getelementptr inbounds":
+		// Formato: %dest = getelementptr inbounds <tipo>, <tipo>* <ptr>, <índices>
+		return fmt.Sprintf("%s = %s %s, %s", i.Dest, i.Op, i.Type, strings.Join(i.Args, ", "))
 	case "load":
 		return fmt.Sprintf("%s = %s %s, %s %s", i.Dest, i.Op, i.Type, i.Args[0], i.Args[1])
 	case "store":
@@ -160,6 +206,8 @@ func (i Instruction) Format() string {
 			return fmt.Sprintf("%s label %%%s", i.Op, i.Args[0])
 		}
 		return fmt.Sprintf("%s i1 %s, label %%%s, label %%%s", i.Op, i.Args[0], i.Args[1], i.Args[2])
+	case "unreachable":
+		return "unreachable"
 	case "ret":
 		if i.Type == "void" {
 			return "ret void"
@@ -0,0 +1,58 @@
+package intermediatecodegeneration
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLLVMStringLiteral(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"abc", "abc"},
+		{"a b", "a b"},
+		{"a\nb", `a\0Ab`},
+		{"\t\r", `\09\0D`},
+		{`"`, `\22`},
+		{`\`, `\5C`},
+		{"\x00", `\00`},
+		{"\x7f", `\7F`},
+		{"é", `\C3\A9`},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := llvmStringLiteral(tt.value); got != tt.want {
+			t.Errorf("llvmStringLiteral(%q) = %s, esperava %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestInternString(t *testing.T) {
+	ir := NewIR()
+
+	// O tamanho é contado em bytes UTF-8, mais o terminador
+	name, length := ir.InternString("ação\n")
+	if length != 8 {
+		t.Errorf("tamanho %d, esperava 8", length)
+	}
+	if len(ir.GlobalVars) != 1 {
+		t.Fatalf("esperava 1 constante global, obteve %d", len(ir.GlobalVars))
+	}
+	decl := ir.GlobalVars[0].Args[0]
+	if !strings.Contains(decl, `[8 x i8] c"a\C3\A7\C3\A3o\0A\00"`) {
+		t.Errorf("declaração inesperada: %s", decl)
+	}
+
+	// Strings iguais reutilizam a mesma constante
+	again, _ := ir.InternString("ação\n")
+	if again != name || len(ir.GlobalVars) != 1 {
+		t.Errorf("a string repetida gerou outra constante: %s, %s", name, again)
+	}
+
+	other, length := ir.InternString("")
+	if other == name || length != 1 {
+		t.Errorf("string vazia: nome %s, tamanho %d", other, length)
+	}
+}
@@ -0,0 +1,44 @@
+package intermediatecodegeneration
+
+// SymbolTable guarda as variáveis visíveis durante a geração de código, com
+// escopos aninhados no mesmo modelo de parser.SymbolTable
+type SymbolTable struct {
+	scopes []map[string]VariableInfo
+}
+
+// Cria nova tabela de símbolos com escopo global
+func NewSymbolTable() *SymbolTable {
+	return &SymbolTable{
+		scopes: []map[string]VariableInfo{
+			make(map[string]VariableInfo), // Escopo global
+		},
+	}
+}
+
+// Entra em um novo escopo
+func (st *SymbolTable) PushScope() {
+	st.scopes = append(st.scopes, make(map[string]VariableInfo))
+}
+
+// Sai do escopo atual, restaurando as variáveis que ele sombreava
+func (st *SymbolTable) PopScope() {
+	if len(st.scopes) <= 1 {
+		panic("cannot pop global scope")
+	}
+	st.scopes = st.scopes[:len(st.scopes)-1]
+}
+
+// Declara uma variável no escopo atual, sombreando as dos escopos externos
+func (st *SymbolTable) Declare(name string, info VariableInfo) {
+	st.scopes[len(st.scopes)-1][name] = info
+}
+
+// Resolve procura a variável do escopo mais interno para o global
+func (st *SymbolTable) Resolve(name string) (VariableInfo, bool) {
+	for i := len(st.scopes) - 1; i >= 0; i-- {
+		if info, exists := st.scopes[i][name]; exists {
+			return info, true
+		}
+	}
+	return VariableInfo{}, false
+}
@@ -1,8 +1,11 @@
 package lexer
 
 import (
+	"fmt"
 	"simple-compiler/token"
+	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 type Lexer struct {
@@ -15,12 +18,33 @@ type Lexer struct {
 }
 
 func New(input string) *Lexer {
-	l := &Lexer{input: input, line: 1, column: 1}
+	l := &Lexer{input: input, line: 1, column: 0}
 	l.readChar()
 	return l
 }
 
+// Tokenize lê todos os tokens do código-fonte. O último é sempre o EOF
+func Tokenize(input string) []token.Token {
+	l := New(input)
+	var tokens []token.Token
+	for {
+		tok := l.NextToken()
+		tokens = append(tokens, tok)
+		if tok.Type == token.EOF {
+			return tokens
+		}
+	}
+}
+
+// readChar avança para o próximo caractere. line/column sempre indicam a
+// posição de l.ch, começando em 1:1.
 func (l *Lexer) readChar() {
+	// A quebra de linha pertence à linha anterior; só muda de linha ao sair dela
+	if l.ch == '\n' {
+		l.line++
+		l.column = 0
+	}
+
 	if l.readPosition >= len(l.input) {
 		l.ch = 0 // EOF
 	} else {
@@ -28,44 +52,81 @@ func (l *Lexer) readChar() {
 	}
 	l.position = l.readPosition
 	l.readPosition++
+	l.column++
+}
 
-	// Atualiza posição apenas se não for EOF
-	if l.ch != 0 {
-		if l.ch == '\n' {
-			l.line++
-			l.column = 1
-		} else {
-			l.column++
+// skipWhitespace pula espaços e comentários (// e /* */). Se encontrar um
+// comentário de bloco não finalizado, retorna um token ILLEGAL na posição
+// onde o comentário começa e ok = false.
+func (l *Lexer) skipWhitespace() (illegal token.Token, ok bool) {
+	for {
+		switch {
+		case l.ch != 0 && unicode.IsSpace(rune(l.ch)):
+			l.readChar()
+		case l.ch == '/' && l.peekChar() == '/':
+			l.skipLineComment()
+		case l.ch == '/' && l.peekChar() == '*':
+			start := token.Token{Type: token.ILLEGAL, Line: l.line, Column: l.column}
+			if !l.skipBlockComment() {
+				start.Lexeme = "comentário de bloco não finalizado"
+				return start, false
+			}
+		default:
+			return token.Token{}, true
 		}
 	}
 }
 
-func (l *Lexer) skipWhitespace() {
-	for unicode.IsSpace(rune(l.ch)) && l.ch != 0 {
-		// Mantém o controle preciso de linhas/colunas
-		if l.ch == '\n' {
-			l.line++
-			l.column = 0
-		}
+// skipLineComment pula um comentário // até o fim da linha
+func (l *Lexer) skipLineComment() {
+	for l.ch != '\n' && l.ch != 0 {
 		l.readChar()
 	}
 }
 
+// skipBlockComment pula um comentário /* */, permitindo comentários
+// aninhados. Retorna false se o arquivo terminar antes do fechamento.
+func (l *Lexer) skipBlockComment() bool {
+	depth := 0
+	for l.ch != 0 {
+		switch {
+		case l.ch == '/' && l.peekChar() == '*':
+			depth++
+			l.readChar()
+			l.readChar()
+		case l.ch == '*' && l.peekChar() == '/':
+			depth--
+			l.readChar()
+			l.readChar()
+			if depth == 0 {
+				return true
+			}
+		default:
+			l.readChar()
+		}
+	}
+	return false
+}
+
 var keywords = map[string]token.TokenType{
-	"if":     token.IF,
-	"else":   token.ELSE,
-	"for":    token.FOR,
-	"while":  token.WHILE,
-	"int":    token.TYPE,
-	"float":  token.TYPE,
-	"void":   token.TYPE,
-	"return": token.RETURN,
-	"string": token.TYPE,
-	"bool":   token.TYPE,
-	"true":   token.BOOLEAN,
-	"false":  token.BOOLEAN,
-	"func":   token.FUNC,
-	"print":  token.PRINT, // print é tratado como identificador especial
+	"if":       token.IF,
+	"else":     token.ELSE,
+	"for":      token.FOR,
+	"while":    token.WHILE,
+	"int":      token.TYPE,
+	"float":    token.TYPE,
+	"void":     token.TYPE,
+	"return":   token.RETURN,
+	"string":   token.TYPE,
+	"bool":     token.TYPE,
+	"true":     token.BOOLEAN,
+	"false":    token.BOOLEAN,
+	"func":     token.FUNC,
+	"print":    token.PRINT, // print é tratado como identificador especial
+	"break":    token.BREAK,
+	"continue": token.CONTINUE,
+	"new":      token.NEW,
+	"struct":   token.STRUCT,
 
 }
 
@@ -77,23 +138,106 @@ func (l *Lexer) readIdentifier() string {
 	return l.input[start:l.position]
 }
 
-func (l *Lexer) readString() string {
+// readString lê uma string entre aspas interpretando as sequências de escape
+// (\n, \t, \r, \\, \", \xNN e \u{...}). O valor retornado já contém os
+// bytes finais da string, em UTF-8. Em caso de erro retorna um token ILLEGAL
+// na posição do problema e ok = false; mesmo assim a string é lida até a aspa
+// final, para que o restante dela não seja analisado como código.
+func (l *Lexer) readString() (value string, illegal token.Token, ok bool) {
+	start := token.Token{Type: token.ILLEGAL, Line: l.line, Column: l.column}
+	fail := func(line, column int, msg string) {
+		if ok {
+			illegal = token.Token{Type: token.ILLEGAL, Line: line, Column: column, Lexeme: msg}
+			ok = false
+		}
+	}
+
+	var sb strings.Builder
+	ok = true
 	l.readChar() // Pula a aspa inicial
-	start := l.position
 
-	for l.ch != '"' && l.ch != 0 {
+	for l.ch != '"' {
+		if l.ch == 0 {
+			start.Lexeme = "string não finalizada"
+			return "", start, false
+		}
+		if l.ch != '\\' {
+			sb.WriteByte(l.ch)
+			l.readChar()
+			continue
+		}
+
+		line, column := l.line, l.column
+		l.readChar() // Pula a barra
+		switch l.ch {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case '\\', '"':
+			sb.WriteByte(l.ch)
+		case 'x':
+			b, digits := l.readHexDigits(2)
+			if digits != 2 {
+				fail(line, column, "escape \\x exige 2 dígitos hexadecimais")
+			}
+			sb.WriteByte(byte(b))
+		case 'u':
+			if l.peekChar() != '{' {
+				fail(line, column, "escape \\u exige o formato \\u{...}")
+				break
+			}
+			l.readChar() // Vai para '{'
+			r, digits := l.readHexDigits(6)
+			if digits == 0 || l.peekChar() != '}' {
+				fail(line, column, "escape \\u{...} exige de 1 a 6 dígitos hexadecimais")
+				break
+			}
+			l.readChar() // Vai para '}'
+			if !utf8.ValidRune(rune(r)) {
+				fail(line, column, fmt.Sprintf("código Unicode inválido: \\u{%X}", r))
+				break
+			}
+			sb.WriteRune(rune(r))
+		case 0:
+			continue // O laço reporta a string não finalizada
+		default:
+			fail(line, column, fmt.Sprintf("sequência de escape inválida: \\%c", l.ch))
+		}
 		l.readChar()
 	}
 
-	if l.ch == '"' {
-		str := l.input[start:l.position]
-		l.readChar() // Pula a aspa final
-		if str == "" {
-			return " " // Retorna espaço para strings vazias
+	l.readChar() // Pula a aspa final
+	return sb.String(), illegal, ok
+}
+
+// readHexDigits consome até max dígitos hexadecimais após o caractere atual,
+// parando no último dígito lido. Retorna o valor e quantos dígitos foram lidos
+func (l *Lexer) readHexDigits(max int) (value int, digits int) {
+	for digits < max {
+		d, isHex := hexDigit(l.peekChar())
+		if !isHex {
+			break
 		}
-		return str
+		l.readChar()
+		value = value<<4 | d
+		digits++
+	}
+	return value, digits
+}
+
+func hexDigit(ch byte) (int, bool) {
+	switch {
+	case '0' <= ch && ch <= '9':
+		return int(ch - '0'), true
+	case 'a' <= ch && ch <= 'f':
+		return int(ch-'a') + 10, true
+	case 'A' <= ch && ch <= 'F':
+		return int(ch-'A') + 10, true
 	}
-	return "" // Indica string não finalizada
+	return 0, false
 }
 
 func (l *Lexer) readNumber() string {
@@ -121,7 +265,9 @@ func (l *Lexer) peekChar() byte {
 }
 
 func (l *Lexer) NextToken() token.Token {
-	l.skipWhitespace()
+	if illegal, ok := l.skipWhitespace(); !ok {
+		return illegal
+	}
 
 	tok := token.Token{
 		Line:   l.line,
@@ -131,16 +277,18 @@ func (l *Lexer) NextToken() token.Token {
 	case 0:
 		tok.Type = token.EOF
 		tok.Lexeme = ""
-    case '"':
-        str := l.readString()
-        if str == "" {
-            tok.Type = token.ILLEGAL
-            tok.Lexeme = "string não finalizada"
-        } else {
-            tok.Type = token.STRING_LITERAL
-            tok.Lexeme = str
-        }
-        return tok
+	case '"':
+		start := l.position
+		str, illegal, ok := l.readString()
+		if !ok {
+			return illegal
+		}
+		// O lexema guarda o texto original, com aspas e escapes, para que os
+		// diagnósticos marquem o literal inteiro
+		tok.Type = token.STRING_LITERAL
+		tok.Lexeme = l.input[start:l.position]
+		tok.Value = str
+		return tok
 	case '=':
 		if l.peekChar() == '=' {
 			tok.Lexeme = "=="
@@ -159,6 +307,26 @@ func (l *Lexer) NextToken() token.Token {
 			tok.Lexeme = "!"
 			tok.Type = token.NOT
 		}
+	case '&':
+		if l.peekChar() == '&' {
+			tok.Lexeme = "&&"
+			tok.Type = token.AND
+			l.readChar()
+		} else {
+			tok.Type = token.ILLEGAL
+			tok.Lexeme = "&"
+			l.readChar()
+		}
+	case '|':
+		if l.peekChar() == '|' {
+			tok.Lexeme = "||"
+			tok.Type = token.OR
+			l.readChar()
+		} else {
+			tok.Type = token.ILLEGAL
+			tok.Lexeme = "|"
+			l.readChar()
+		}
 	case '+':
 		tok.Lexeme = "+"
 		tok.Type = token.PLUS
@@ -204,6 +372,18 @@ func (l *Lexer) NextToken() token.Token {
 	case '}':
 		tok.Lexeme = "}"
 		tok.Type = token.RBRACE
+	case '.':
+		tok.Lexeme = "."
+		tok.Type = token.DOT
+	case ':':
+		tok.Lexeme = ":"
+		tok.Type = token.COLON
+	case '[':
+		tok.Lexeme = "["
+		tok.Type = token.LBRACKET
+	case ']':
+		tok.Lexeme = "]"
+		tok.Type = token.RBRACKET
 	case ',':
         tok.Type = token.COMMA  // Corrigido para usar a constante COMMA
         tok.Lexeme = ","
@@ -0,0 +1,169 @@
+package lexer
+
+import (
+	"simple-compiler/token"
+	"strings"
+	"testing"
+)
+
+func TestStringEscapes(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`"abc"`, "abc"},
+		{`"a\nb"`, "a\nb"},
+		{`"\t\r"`, "\t\r"},
+		{`"barra \\ aspas \""`, `barra \ aspas "`},
+		{`"\x41\x7a"`, "Az"},
+		{`"\x00"`, "\x00"},
+		{`"\u{41}"`, "A"},
+		{`"\u{E9}"`, "é"},
+		{`"\u{1F600}"`, "\U0001F600"},
+		{`"ação"`, "ação"},
+	}
+
+	for _, tt := range tests {
+		tok := New(tt.src).NextToken()
+		if tok.Type != token.STRING_LITERAL {
+			t.Errorf("%s: esperava STRING_LITERAL, obteve %s (%q)", tt.src, tok.Type, tok.Lexeme)
+			continue
+		}
+		if tok.Value != tt.want {
+			t.Errorf("%s: esperava %q, obteve %q", tt.src, tt.want, tok.Value)
+		}
+		// O lexema é o texto original, usado nos trechos dos diagnósticos
+		if tok.Lexeme != tt.src {
+			t.Errorf("%s: lexema %q, esperava o texto original", tt.src, tok.Lexeme)
+		}
+	}
+}
+
+func TestStringEscapesInvalidos(t *testing.T) {
+	tests := []struct {
+		src    string
+		column int
+		msg    string
+	}{
+		{`"a\qb"`, 3, "sequência de escape inválida"},
+		{`"\x4"`, 2, "escape \\x exige 2 dígitos hexadecimais"},
+		{`"\xg0"`, 2, "escape \\x exige 2 dígitos hexadecimais"},
+		{`"\u41"`, 2, "escape \\u exige o formato"},
+		{`"\u{}"`, 2, "exige de 1 a 6 dígitos"},
+		{`"\u{1234567}"`, 2, "exige de 1 a 6 dígitos"},
+		{`"\u{D800}"`, 2, "código Unicode inválido"},
+		{`"\u{110000}"`, 2, "código Unicode inválido"},
+		{`"abc`, 1, "string não finalizada"},
+	}
+
+	for _, tt := range tests {
+		l := New(tt.src)
+		tok := l.NextToken()
+		if tok.Type != token.ILLEGAL {
+			t.Errorf("%s: esperava ILLEGAL, obteve %s (%q)", tt.src, tok.Type, tok.Lexeme)
+			continue
+		}
+		if !strings.Contains(tok.Lexeme, tt.msg) {
+			t.Errorf("%s: esperava mensagem com %q, obteve %q", tt.src, tt.msg, tok.Lexeme)
+		}
+		if tok.Line != 1 || tok.Column != tt.column {
+			t.Errorf("%s: esperava posição 1:%d, obteve %d:%d", tt.src, tt.column, tok.Line, tok.Column)
+		}
+		// O restante da string não pode ser lido como código
+		if next := l.NextToken(); next.Type != token.EOF {
+			t.Errorf("%s: esperava EOF após a string, obteve %s (%q)", tt.src, next.Type, next.Lexeme)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tokens := Tokenize("int x = 1\nprint(x)")
+	want := []token.TokenType{
+		token.TYPE, token.IDENTIFIER, token.ASSIGN, token.NUMBER,
+		token.PRINT, token.LPAREN, token.IDENTIFIER, token.RPAREN, token.EOF,
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("esperava %d tokens, obteve %d: %v", len(want), len(tokens), tokens)
+	}
+	for i, tok := range tokens {
+		if tok.Type != want[i] {
+			t.Errorf("token %d: esperava %s, obteve %s (%q)", i, want[i], tok.Type, tok.Lexeme)
+		}
+	}
+
+	if tokens := Tokenize(""); len(tokens) != 1 || tokens[0].Type != token.EOF {
+		t.Errorf("código vazio deveria gerar só o EOF, obteve %v", tokens)
+	}
+}
+
+func TestComentarios(t *testing.T) {
+	type want struct {
+		typ          token.TokenType
+		lexeme       string
+		line, column int
+	}
+	tests := []struct {
+		name string
+		src  string
+		want []want
+	}{
+		{
+			name: "comentário de linha",
+			src:  "x // resto ignorado\ny",
+			want: []want{{token.IDENTIFIER, "x", 1, 1}, {token.IDENTIFIER, "y", 2, 1}},
+		},
+		{
+			name: "comentário de linha no fim do arquivo",
+			src:  "x //",
+			want: []want{{token.IDENTIFIER, "x", 1, 1}},
+		},
+		{
+			name: "comentário de bloco no meio da linha",
+			src:  "a /* b */ c",
+			want: []want{{token.IDENTIFIER, "a", 1, 1}, {token.IDENTIFIER, "c", 1, 11}},
+		},
+		{
+			name: "comentário de bloco em várias linhas",
+			src:  "a /* linha 1\nlinha 2\n */ b\nc",
+			want: []want{{token.IDENTIFIER, "a", 1, 1}, {token.IDENTIFIER, "b", 3, 5}, {token.IDENTIFIER, "c", 4, 1}},
+		},
+		{
+			name: "comentários de bloco aninhados",
+			src:  "/* externo /* interno */ ainda externo */ x",
+			want: []want{{token.IDENTIFIER, "x", 1, 43}},
+		},
+		{
+			name: "// dentro de comentário de bloco",
+			src:  "/* // */ x",
+			want: []want{{token.IDENTIFIER, "x", 1, 10}},
+		},
+		{
+			name: "divisão não é comentário",
+			src:  "a / b",
+			want: []want{{token.IDENTIFIER, "a", 1, 1}, {token.DIV, "/", 1, 3}, {token.IDENTIFIER, "b", 1, 5}},
+		},
+		{
+			name: "comentário de bloco não finalizado",
+			src:  "x\n  /* /* */ sem fim",
+			want: []want{{token.IDENTIFIER, "x", 1, 1}, {token.ILLEGAL, "comentário de bloco não finalizado", 2, 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := Tokenize(tt.src)
+			// O EOF final não faz parte dos casos
+			tokens = tokens[:len(tokens)-1]
+			if len(tokens) != len(tt.want) {
+				t.Fatalf("esperava %d tokens, obteve %d: %v", len(tt.want), len(tokens), tokens)
+			}
+			for i, tok := range tokens {
+				w := tt.want[i]
+				if tok.Type != w.typ || tok.Lexeme != w.lexeme || tok.Line != w.line || tok.Column != w.column {
+					t.Errorf("token %d: esperava %s %q em %d:%d, obteve %s %q em %d:%d", i,
+						w.typ, w.lexeme, w.line, w.column, tok.Type, tok.Lexeme, tok.Line, tok.Column)
+				}
+			}
+		})
+	}
+}
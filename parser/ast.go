@@ -27,8 +27,9 @@ type Statement interface {
 
 // Identifier representa uma variável
 type Identifier struct {
-	Name  string
-	Token token.Token
+	Name   string
+	Token  token.Token
+	Symbol *SymbolInfo // Declaração correspondente, preenchida pelo resolvedor de nomes
 }
 
 func (i *Identifier) exprNode() {}
@@ -44,10 +45,18 @@ type Number struct {
 
 func (n *Number) exprNode() {}
 func (n *Number) String() string {
+	if n.Token.Lexeme != "" {
+		return n.Token.Lexeme
+	}
 	return fmt.Sprintf("%v", n.Value)
 }
 
-// IfStatement representa uma estrutura condicional
+// IsFloat indica se o literal é float. O tipo vem da forma como o número foi
+// escrito, não do valor: 2.0 é float e 2 é int
+func (n *Number) IsFloat() bool {
+	return strings.Contains(n.Token.Lexeme, ".")
+}
+
 // IfStatement representa uma estrutura condicional
 type IfStatement struct {
 	Condition Expression
@@ -74,6 +83,25 @@ func (i *IfStatement) String() string {
 	}
 
 	sb.WriteString("}")
+
+	// Else (ou cadeia de else if)
+	if i.ElseBody != nil {
+		if len(i.ElseBody.Statements) == 1 {
+			if elseIf, ok := i.ElseBody.Statements[0].(*IfStatement); ok {
+				sb.WriteString(" else ")
+				sb.WriteString(elseIf.String())
+				return sb.String()
+			}
+		}
+
+		sb.WriteString(" else {\n")
+		for _, stmt := range i.ElseBody.Statements {
+			sb.WriteString("    ")
+			sb.WriteString(stmt.String())
+			sb.WriteString("\n")
+		}
+		sb.WriteString("}")
+	}
 	return sb.String()
 }
 
@@ -137,6 +165,24 @@ func (f *ForStatement) String() string {
 	return fmt.Sprintf("for (%s; %s; %s) {%s\n}", initStr, condStr, updateStr, bodyStr)
 }
 
+// BreakStatement interrompe o laço mais interno
+type BreakStatement struct {
+	Token token.Token
+}
+
+func (b *BreakStatement) stmtNode()             {}
+func (b *BreakStatement) String() string        { return "break" }
+func (b *BreakStatement) GetToken() token.Token { return b.Token }
+
+// ContinueStatement salta para a próxima iteração do laço mais interno
+type ContinueStatement struct {
+	Token token.Token
+}
+
+func (c *ContinueStatement) stmtNode()             {}
+func (c *ContinueStatement) String() string        { return "continue" }
+func (c *ContinueStatement) GetToken() token.Token { return c.Token }
+
 // BinaryExpression representa operações entre dois operandos
 type BinaryExpression struct {
 	Left     Expression
@@ -152,9 +198,11 @@ func (b *BinaryExpression) String() string {
 
 // AssignmentStatement representa uma atribuição de variável
 type AssignmentStatement struct {
-	Name  string
-	Value Expression
-	Token token.Token
+	Name      string
+	Value     Expression
+	Token     token.Token // O '='
+	NameToken token.Token // O nome da variável, onde os erros da atribuição são apontados
+	Symbol    *SymbolInfo // Variável atribuída, preenchida pelo resolvedor de nomes
 }
 
 func (a *AssignmentStatement) GetToken() token.Token {
@@ -189,6 +237,7 @@ func (v *VariableDeclaration) String() string {
 // ReturnStatement representa um retorno de função
 type ReturnStatement struct {
 	Value Expression
+	Token token.Token
 }
 
 func (rs *ReturnStatement) stmtNode() {}
@@ -269,11 +318,14 @@ type StringLiteral struct {
 }
 
 func (s *StringLiteral) exprNode()             {}
-func (s *StringLiteral) String() string        { return fmt.Sprintf("\"%s\"", s.Value) }
+func (s *StringLiteral) String() string        { return fmt.Sprintf("%q", s.Value) }
 func (s *StringLiteral) GetToken() token.Token { return s.Token }
 
 // BinaryExpression
 func (b *BinaryExpression) GetToken() token.Token {
+	if b.Token.Type != "" {
+		return b.Token
+	}
 	return token.Token{
 		Type:   tokenTypeFromOperator(b.Operator),
 		Lexeme: b.Operator,
@@ -296,6 +348,9 @@ func (u *UnaryExpression) GetToken() token.Token {
 
 // Number
 func (n *Number) GetToken() token.Token {
+	if n.Token.Type != "" {
+		return n.Token
+	}
 	lexeme := fmt.Sprintf("%v", n.Value)
 	if n.Value == float64(int(n.Value)) {
 		lexeme = fmt.Sprintf("%d", int(n.Value))
@@ -308,6 +363,9 @@ func (n *Number) GetToken() token.Token {
 
 // Identifier
 func (i *Identifier) GetToken() token.Token {
+	if i.Token.Type != "" {
+		return i.Token
+	}
 	return token.Token{
 		Type:   token.IDENTIFIER,
 		Lexeme: i.Name,
@@ -348,6 +406,9 @@ func (b *BlockStatement) GetToken() token.Token {
 
 // ReturnStatement
 func (r *ReturnStatement) GetToken() token.Token {
+	if r.Token.Type != "" {
+		return r.Token
+	}
 	return token.Token{
 		Type:   token.RETURN,
 		Lexeme: "return",
@@ -379,6 +440,8 @@ func tokenTypeFromOperator(op string) token.TokenType {
 		return token.LTE
 	case "==":
 		return token.EQ
+	case "!=":
+		return token.NOT_EQ
 	case "=":
 		return token.ASSIGN
 	case "&&":
@@ -396,6 +459,7 @@ type CallExpression struct {
     FunctionName string
     Arguments    []Expression
     Token        token.Token
+    Symbol       *SymbolInfo // Função chamada (nil para builtins), preenchida pelo resolvedor de nomes
 }
 
 func (c *CallExpression) exprNode() {}
@@ -407,3 +471,135 @@ func (c *CallExpression) String() string {
     }
     return fmt.Sprintf("%s(%s)", c.FunctionName, strings.Join(args, ", "))
 }
+
+// IndexExpression representa o acesso a um elemento de array (xs[i])
+type IndexExpression struct {
+	Array Expression
+	Index Expression
+	Token token.Token // O '['
+}
+
+func (ie *IndexExpression) exprNode()             {}
+func (ie *IndexExpression) GetToken() token.Token { return ie.Token }
+func (ie *IndexExpression) String() string {
+	return fmt.Sprintf("%s[%s]", ie.Array.String(), ie.Index.String())
+}
+
+// IndexAssignmentStatement representa a atribuição a um elemento de array
+// (xs[i] = valor)
+type IndexAssignmentStatement struct {
+	Target *IndexExpression
+	Value  Expression
+	Token  token.Token // O '='
+}
+
+func (ia *IndexAssignmentStatement) stmtNode()             {}
+func (ia *IndexAssignmentStatement) GetToken() token.Token { return ia.Token }
+func (ia *IndexAssignmentStatement) String() string {
+	return fmt.Sprintf("%s = %s", ia.Target.String(), ia.Value.String())
+}
+
+// NewArrayExpression representa a criação de um array no heap (new int[n])
+type NewArrayExpression struct {
+	ElementType string
+	Size        Expression
+	Token       token.Token // O 'new'
+}
+
+func (na *NewArrayExpression) exprNode()             {}
+func (na *NewArrayExpression) GetToken() token.Token { return na.Token }
+func (na *NewArrayExpression) String() string {
+	return fmt.Sprintf("new %s[%s]", na.ElementType, na.Size.String())
+}
+
+// CastExpression representa uma conversão explícita entre tipos (int(x), float(n))
+type CastExpression struct {
+	Type  string
+	Value Expression
+	Token token.Token // O nome do tipo
+}
+
+func (ce *CastExpression) exprNode()             {}
+func (ce *CastExpression) GetToken() token.Token { return ce.Token }
+func (ce *CastExpression) String() string {
+	return fmt.Sprintf("%s(%s)", ce.Type, ce.Value.String())
+}
+
+// StructDeclaration representa a definição de um tipo struct
+type StructDeclaration struct {
+	Name   string
+	Fields []*VariableDeclaration
+	Token  token.Token // O nome da struct
+}
+
+func (sd *StructDeclaration) stmtNode()             {}
+func (sd *StructDeclaration) GetToken() token.Token { return sd.Token }
+func (sd *StructDeclaration) String() string {
+	fields := make([]string, len(sd.Fields))
+	for i, field := range sd.Fields {
+		fields[i] = fmt.Sprintf("%s %s", field.Type, field.Name)
+	}
+	return fmt.Sprintf("struct %s { %s }", sd.Name, strings.Join(fields, "; "))
+}
+
+// Field retorna o campo com o nome informado e a sua posição na struct
+func (sd *StructDeclaration) Field(name string) (*VariableDeclaration, int) {
+	for i, field := range sd.Fields {
+		if field.Name == name {
+			return field, i
+		}
+	}
+	return nil, -1
+}
+
+// MemberExpression representa o acesso a um campo de struct (p.x)
+type MemberExpression struct {
+	Object Expression
+	Field  string
+	Token  token.Token // O nome do campo
+}
+
+func (me *MemberExpression) exprNode()             {}
+func (me *MemberExpression) GetToken() token.Token { return me.Token }
+func (me *MemberExpression) String() string {
+	return fmt.Sprintf("%s.%s", me.Object.String(), me.Field)
+}
+
+// FieldAssignmentStatement representa a atribuição a um campo de struct
+// (p.x = valor)
+type FieldAssignmentStatement struct {
+	Target *MemberExpression
+	Value  Expression
+	Token  token.Token // O '='
+}
+
+func (fa *FieldAssignmentStatement) stmtNode()             {}
+func (fa *FieldAssignmentStatement) GetToken() token.Token { return fa.Token }
+func (fa *FieldAssignmentStatement) String() string {
+	return fmt.Sprintf("%s = %s", fa.Target.String(), fa.Value.String())
+}
+
+// StructLiteral representa a criação de um valor struct (Point{x: 1, y: 2.0}).
+// Campos omitidos começam zerados
+type StructLiteral struct {
+	TypeName string
+	Fields   []FieldValue
+	Token    token.Token // O nome do tipo
+}
+
+// FieldValue é um campo inicializado em um StructLiteral
+type FieldValue struct {
+	Name  string
+	Value Expression
+	Token token.Token // O nome do campo
+}
+
+func (sl *StructLiteral) exprNode()             {}
+func (sl *StructLiteral) GetToken() token.Token { return sl.Token }
+func (sl *StructLiteral) String() string {
+	fields := make([]string, len(sl.Fields))
+	for i, field := range sl.Fields {
+		fields[i] = fmt.Sprintf("%s: %s", field.Name, field.Value.String())
+	}
+	return fmt.Sprintf("%s{%s}", sl.TypeName, strings.Join(fields, ", "))
+}
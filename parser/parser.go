@@ -7,12 +7,13 @@ import (
 )
 
 // Parser estrutura que gerencia a análise sintática
+// A análise é puramente sintática: nomes são resolvidos depois, pelo
+// resolvedor da análise semântica
 type Parser struct {
-	tokens      []token.Token
-	pos         int
-	current     token.Token
-	symbolTable *SymbolTable
-	Errors      []ParseError
+	tokens  []token.Token
+	pos     int
+	current token.Token
+	Errors  []ParseError
 }
 
 type ParseError struct {
@@ -24,9 +25,8 @@ type ParseError struct {
 
 func New(tokens []token.Token) *Parser {
 	p := &Parser{
-		tokens:      tokens,
-		symbolTable: NewSymbolTable(), // Certifique-se que está inicializando aqui
-		Errors:      make([]ParseError, 0),
+		tokens: tokens,
+		Errors: make([]ParseError, 0),
 	}
 	p.nextToken()
 	return p
@@ -67,6 +67,9 @@ func (p *Parser) parseAssignment() Expression {
 			opToken := p.current
 			p.nextToken()
 			value := p.parseAssignment()
+			if value == nil {
+				return nil
+			}
 			return &BinaryExpression{
 				Left:     ident,
 				Operator: opToken.Lexeme,
@@ -74,8 +77,14 @@ func (p *Parser) parseAssignment() Expression {
 				Token:    opToken,
 			}
 		}
-		p.addError("Esperado identificador no lado esquerdo da atribuição",
-			p.current.Line, p.current.Column)
+		// xs[i] = valor e p.x = valor são comandos, tratados por
+		// ParseAssignmentOrExpression
+		switch expr.(type) {
+		case *IndexExpression, *MemberExpression:
+		default:
+			p.addError("Esperado identificador no lado esquerdo da atribuição",
+				p.current.Line, p.current.Column)
+		}
 	}
 
 	return expr
@@ -85,12 +94,6 @@ func (p *Parser) ParseAssignment() Statement {
 	name := p.current.Lexeme
 	currentToken := p.current
 
-	// Verifica se a variável foi declarada
-	if _, exists := p.symbolTable.Resolve(name); !exists {
-		p.addError(fmt.Sprintf("Variável '%s' não declarada", name),
-			currentToken.Line, currentToken.Column)
-	}
-
 	p.nextToken() // Pula o nome da variável
 
 	// CORREÇÃO: Compare p.current.Type com token.ASSIGN
@@ -103,30 +106,59 @@ func (p *Parser) ParseAssignment() Statement {
 	p.nextToken() // Pula o '='
 
 	value := p.parseExpression()
+	if value == nil {
+		return nil
+	}
 
 	return &AssignmentStatement{
-		Name:  name,
-		Value: value,
-		Token: assignToken,
+		Name:      name,
+		Value:     value,
+		Token:     assignToken,
+		NameToken: currentToken,
 	}
 }
 
 func (p *Parser) ParseAssignmentOrExpression() Statement {
-	name := p.current.Lexeme
-
 	// Verifica se é uma atribuição
 	if p.peekToken().Type == token.ASSIGN {
 		return p.ParseAssignment()
 	}
 
-	// Verifica se a variável foi declarada
-	if _, exists := p.symbolTable.Resolve(name); !exists {
-		p.addError(fmt.Sprintf("Variável '%s' não declarada", name),
-			p.current.Line, p.current.Column)
-	}
-
 	// Processa como expressão
 	expr := p.parseExpression()
+	if expr == nil {
+		return nil
+	}
+
+	// Atribuição a um elemento de array: xs[i] = valor
+	if target, ok := expr.(*IndexExpression); ok && p.current.Type == token.ASSIGN {
+		assignToken := p.current
+		p.nextToken() // Pula o '='
+		value := p.parseExpression()
+		if value == nil {
+			return nil
+		}
+		return &IndexAssignmentStatement{
+			Target: target,
+			Value:  value,
+			Token:  assignToken,
+		}
+	}
+
+	// Atribuição a um campo de struct: p.x = valor
+	if target, ok := expr.(*MemberExpression); ok && p.current.Type == token.ASSIGN {
+		assignToken := p.current
+		p.nextToken() // Pula o '='
+		value := p.parseExpression()
+		if value == nil {
+			return nil
+		}
+		return &FieldAssignmentStatement{
+			Target: target,
+			Value:  value,
+			Token:  assignToken,
+		}
+	}
 	return &ExpressionStatement{Expression: expr}
 }
 
@@ -137,6 +169,9 @@ func (p *Parser) parseLogicalOr() Expression {
 		opToken := p.current
 		p.nextToken()
 		right := p.parseLogicalAnd()
+		if expr == nil || right == nil {
+			return nil // O erro já foi registrado
+		}
 		expr = &BinaryExpression{
 			Left:     expr,
 			Operator: opToken.Lexeme,
@@ -155,6 +190,9 @@ func (p *Parser) parseLogicalAnd() Expression {
 		opToken := p.current
 		p.nextToken()
 		right := p.parseEquality()
+		if expr == nil || right == nil {
+			return nil // O erro já foi registrado
+		}
 		expr = &BinaryExpression{
 			Left:     expr,
 			Operator: opToken.Lexeme,
@@ -169,10 +207,13 @@ func (p *Parser) parseLogicalAnd() Expression {
 func (p *Parser) parseEquality() Expression {
 	expr := p.parseComparison()
 
-	for p.current.Type == token.EQ {
+	for p.current.Type == token.EQ || p.current.Type == token.NOT_EQ {
 		opToken := p.current
 		p.nextToken()
 		right := p.parseComparison()
+		if expr == nil || right == nil {
+			return nil // O erro já foi registrado
+		}
 		expr = &BinaryExpression{
 			Left:     expr,
 			Operator: opToken.Lexeme,
@@ -192,6 +233,9 @@ func (p *Parser) parseComparison() Expression {
 		opToken := p.current
 		p.nextToken()
 		right := p.parseAddition()
+		if expr == nil || right == nil {
+			return nil // O erro já foi registrado
+		}
 		expr = &BinaryExpression{
 			Left:     expr,
 			Operator: opToken.Lexeme,
@@ -210,6 +254,9 @@ func (p *Parser) parseAddition() Expression {
 		opToken := p.current
 		p.nextToken()
 		right := p.parseMultiplication()
+		if expr == nil || right == nil {
+			return nil // O erro já foi registrado
+		}
 		expr = &BinaryExpression{
 			Left:     expr,
 			Operator: opToken.Lexeme,
@@ -228,6 +275,9 @@ func (p *Parser) parseMultiplication() Expression {
 		opToken := p.current
 		p.nextToken()
 		right := p.parseUnary()
+		if expr == nil || right == nil {
+			return nil // O erro já foi registrado
+		}
 		expr = &BinaryExpression{
 			Left:     expr,
 			Operator: opToken.Lexeme,
@@ -243,16 +293,60 @@ func (p *Parser) parseUnary() Expression {
 	if p.current.Type == token.MINUS || p.current.Type == token.NOT {
 		opToken := p.current
 		p.nextToken()
+		// Recursivo para aceitar operadores encadeados como --x e !!flag
+		right := p.parseUnary()
+		if right == nil {
+			return nil
+		}
 		return &UnaryExpression{
 			Operator: opToken.Lexeme,
-			Right:    p.parsePrimary(),
+			Right:    right,
 			Token:    opToken,
 		}
 	}
-	return p.parsePrimary()
+	return p.parsePostfix(p.parsePrimary())
+}
+
+// parsePostfix trata os acessos a elementos e campos que seguem uma
+// expressão, como em xs[i], f()[0] ou ps[0].x
+func (p *Parser) parsePostfix(expr Expression) Expression {
+	for expr != nil && (p.current.Type == token.LBRACKET || p.current.Type == token.DOT) {
+		if p.current.Type == token.DOT {
+			p.nextToken() // Pula '.'
+			if p.current.Type != token.IDENTIFIER {
+				p.addError("Esperado nome do campo após '.'", p.current.Line, p.current.Column)
+				return nil
+			}
+			expr = &MemberExpression{Object: expr, Field: p.current.Lexeme, Token: p.current}
+			p.nextToken()
+			continue
+		}
+
+		bracketToken := p.current
+		p.nextToken() // Pula '['
+
+		index := p.parseExpression()
+		if index == nil {
+			return nil
+		}
+		if p.current.Type != token.RBRACKET {
+			p.addError("Esperado ']' após índice", p.current.Line, p.current.Column)
+			return nil
+		}
+		p.nextToken() // Pula ']'
+
+		expr = &IndexExpression{Array: expr, Index: index, Token: bracketToken}
+	}
+	return expr
 }
 
 func (p *Parser) parsePrimary() Expression {
+	// Um tipo seguido de '(' é uma conversão; sozinho, cai no erro de token
+	// inesperado sem ser consumido, pois inicia declarações
+	if p.current.Type == token.TYPE && p.peekToken().Type == token.LPAREN {
+		return p.parseCast()
+	}
+
 	switch p.current.Type {
 	case token.NUMBER:
 		value, err := strconv.ParseFloat(p.current.Lexeme, 64)
@@ -269,6 +363,9 @@ func (p *Parser) parsePrimary() Expression {
 		if p.peekToken().Type == token.LPAREN { // Chamada de função
 			return p.parseCallExpression()
 		}
+		if p.atStructLiteral() {
+			return p.parseStructLiteral()
+		}
 		expr := &Identifier{Name: p.current.Lexeme, Token: p.current}
 		p.nextToken()
 		return expr
@@ -290,16 +387,130 @@ func (p *Parser) parsePrimary() Expression {
 		return expr
 
 	case token.STRING_LITERAL:
-		expr := &StringLiteral{Value: p.current.Lexeme, Token: p.current}
+		expr := &StringLiteral{Value: p.current.Value, Token: p.current}
 		p.nextToken()
 		return expr
 
+	case token.NEW:
+		return p.parseNewArray()
+
+	case token.ILLEGAL:
+		p.addIllegalTokenError()
+		return nil
+
 	default:
 		p.addError(fmt.Sprintf("Token inesperado: %s", p.current.Lexeme),
 			p.current.Line, p.current.Column)
+		// Pontos de sincronização e blocos ficam para a recuperação do comando
+		if !p.atSyncPoint() && p.current.Type != token.LBRACE {
+			p.nextToken()
+		}
+		return nil
+	}
+}
+
+// parseNewArray analisa a criação de um array no heap: new tipo[tamanho]
+func (p *Parser) parseNewArray() Expression {
+	newToken := p.current
+	p.nextToken() // Pula 'new'
+
+	if p.current.Type != token.TYPE && p.current.Type != token.IDENTIFIER {
+		p.addError("Esperado tipo dos elementos após 'new'", p.current.Line, p.current.Column)
+		return nil
+	}
+	elementType := p.current.Lexeme
+	p.nextToken()
+
+	if p.current.Type != token.LBRACKET {
+		p.addError(fmt.Sprintf("Esperado '[' após 'new %s'", elementType),
+			p.current.Line, p.current.Column)
+		return nil
+	}
+	p.nextToken() // Pula '['
+
+	size := p.parseExpression()
+	if size == nil {
+		return nil
+	}
+	if p.current.Type != token.RBRACKET {
+		p.addError("Esperado ']' após tamanho do array", p.current.Line, p.current.Column)
+		return nil
+	}
+	p.nextToken() // Pula ']'
+
+	return &NewArrayExpression{ElementType: elementType, Size: size, Token: newToken}
+}
+
+// parseCast analisa uma conversão explícita: tipo(valor)
+func (p *Parser) parseCast() Expression {
+	typeToken := p.current
+	p.nextToken() // Pula o tipo
+	p.nextToken() // Pula '('
+
+	value := p.parseExpression()
+	if value == nil {
+		return nil
+	}
+	if p.current.Type != token.RPAREN {
+		p.addError(fmt.Sprintf("Esperado ')' após o valor convertido para %s", typeToken.Lexeme),
+			p.current.Line, p.current.Column)
+		return nil
+	}
+	p.nextToken() // Pula ')'
+
+	return &CastExpression{Type: typeToken.Lexeme, Value: value, Token: typeToken}
+}
+
+// atStructLiteral indica se o identificador atual inicia um literal de struct.
+// O '{' precisa estar na mesma linha e ser seguido de "campo:" ou de '}', para
+// não confundir com um bloco que começa logo após uma expressão
+func (p *Parser) atStructLiteral() bool {
+	brace := p.peekTokenAt(1)
+	if brace.Type != token.LBRACE || brace.Line != p.current.Line {
+		return false
+	}
+	next := p.peekTokenAt(2)
+	return next.Type == token.RBRACE ||
+		(next.Type == token.IDENTIFIER && p.peekTokenAt(3).Type == token.COLON)
+}
+
+// parseStructLiteral analisa Tipo{campo: valor, ...}
+func (p *Parser) parseStructLiteral() Expression {
+	literal := &StructLiteral{TypeName: p.current.Lexeme, Token: p.current}
+	p.nextToken() // Pula o nome do tipo
+	p.nextToken() // Pula '{'
+
+	for p.current.Type != token.RBRACE && !p.AtEnd() {
+		if p.current.Type != token.IDENTIFIER || p.peekToken().Type != token.COLON {
+			p.addError("Esperado 'campo: valor' no literal de struct", p.current.Line, p.current.Column)
+			return nil
+		}
+		fieldToken := p.current
+		p.nextToken() // Pula o nome do campo
+		p.nextToken() // Pula ':'
+
+		value := p.parseExpression()
+		if value == nil {
+			return nil
+		}
+		literal.Fields = append(literal.Fields, FieldValue{
+			Name:  fieldToken.Lexeme,
+			Value: value,
+			Token: fieldToken,
+		})
+		if p.current.Type != token.COMMA {
+			break
+		}
 		p.nextToken()
+	}
+
+	if p.current.Type != token.RBRACE {
+		p.addError("Esperado '}' após campos do literal de struct", p.current.Line, p.current.Column)
 		return nil
 	}
+	p.nextToken() // Pula '}'
+
+	return literal
 }
 
 // ParseStatement analisa comandos como atribuições e estruturas condicionais
@@ -309,9 +520,19 @@ func (p *Parser) ParseStatement() Statement {
 	case token.PRINT:
 		return p.parsePrintStatement()
 	case token.FUNC:
-		return p.parseFunctionDeclaration()
+		// Evita guardar um *FunctionDeclaration nil dentro da interface
+		if fd := p.parseFunctionDeclaration(); fd != nil {
+			return fd
+		}
+		return nil
 	case token.TYPE:
 		return p.ParseVariableDeclaration()
+	case token.STRUCT:
+		// Evita guardar um *StructDeclaration nil dentro da interface
+		if sd := p.parseStructDeclaration(); sd != nil {
+			return sd
+		}
+		return nil
 	case token.IF:
 		return p.parseIfStatement()
 	case token.WHILE:
@@ -320,10 +541,25 @@ func (p *Parser) ParseStatement() Statement {
 		return p.parseForStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.BREAK:
+		stmt := &BreakStatement{Token: p.current}
+		p.nextToken()
+		return stmt
+	case token.CONTINUE:
+		stmt := &ContinueStatement{Token: p.current}
+		p.nextToken()
+		return stmt
 	case token.LBRACE:
 		return p.parseBlock()
 	case token.IDENTIFIER:
+		// Variáveis de tipos struct começam com um identificador: Point p
+		if p.atStructVariableDeclaration() {
+			return p.ParseVariableDeclaration()
+		}
 		return p.ParseAssignmentOrExpression()
+	case token.ILLEGAL:
+		p.addIllegalTokenError()
+		return nil
 	default:
 		p.addError(fmt.Sprintf("Declaração inválida com token %s", p.current.Lexeme),
 			p.current.Line, p.current.Column)
@@ -362,26 +598,34 @@ func (p *Parser) parseIfStatement() Statement {
 		p.addError("Esperado '{' após condição do if", p.current.Line, p.current.Column)
 		return nil
 	}
-	p.nextToken()
+	body := p.parseBlock()
 
-	var body []Statement
-	for p.current.Type != token.RBRACE && !p.AtEnd() {
-		stmt := p.ParseStatement()
-		if stmt != nil {
-			body = append(body, stmt)
+	// Parse do else opcional
+	var elseBody *BlockStatement
+	if p.current.Type == token.ELSE {
+		p.nextToken() // Pula o 'else'
+
+		switch p.current.Type {
+		case token.IF:
+			// else if: o if aninhado vira o único comando do else
+			nested := p.parseIfStatement()
+			if nested == nil {
+				return nil
+			}
+			elseBody = &BlockStatement{Statements: []Statement{nested}}
+		case token.LBRACE:
+			elseBody = p.parseBlock()
+		default:
+			p.addError("Esperado '{' ou 'if' após 'else'", p.current.Line, p.current.Column)
+			return nil
 		}
 	}
 
-	if p.current.Type != token.RBRACE {
-		p.addError("Esperado '}' ao final do bloco if", p.current.Line, p.current.Column)
-		return nil
-	}
-	p.nextToken()
-
 	// Cria e retorna o nó IfStatement
 	return &IfStatement{
 		Condition: condition,
-		Body:      &BlockStatement{Statements: body},
+		Body:      body,
+		ElseBody:  elseBody,
 	}
 }
 func (p *Parser) parseWhileStatement() Statement {
@@ -415,9 +659,6 @@ func (p *Parser) parseWhileStatement() Statement {
 	}
 
 	body := p.parseBlock()
-	if body == nil {
-		return nil
-	}
 
 	return &WhileStatement{
 		Condition: condition,
@@ -433,17 +674,15 @@ func (p *Parser) parseBlock() *BlockStatement {
 
 	block := &BlockStatement{}
 	for p.current.Type != token.RBRACE && !p.AtEnd() {
-		stmt := p.ParseStatement()
-		if stmt != nil {
+		if stmt := p.parseStatementWithRecovery(); stmt != nil {
 			block.Statements = append(block.Statements, stmt)
-		} else {
-			p.nextToken() // Recuperação de erro
 		}
 	}
 
+	// Sem '}' o bloco vai até o fim do arquivo; o que foi lido é mantido
 	if p.current.Type != token.RBRACE {
 		p.addError("Esperado '}' para fechar bloco", p.current.Line, p.current.Column)
-		return nil
+		return block
 	}
 	p.nextToken() // Pula '}'
 
@@ -511,10 +750,13 @@ func (p *Parser) parseForStatement() Statement {
 // Nova função para declaração de variáveis
 func (p *Parser) ParseVariableDeclaration() Statement {
 	typeToken := p.current
-	p.nextToken()
+	typeName, ok := p.parseTypeName()
+	if !ok {
+		return nil
+	}
 
 	if p.current.Type != token.IDENTIFIER {
-		p.addError(fmt.Sprintf("Esperado nome da variável após tipo '%s'", typeToken.Lexeme),
+		p.addError(fmt.Sprintf("Esperado nome da variável após tipo '%s'", typeName),
 			typeToken.Line, typeToken.Column)
 		return nil
 	}
@@ -528,24 +770,120 @@ func (p *Parser) ParseVariableDeclaration() Statement {
 		value = p.parseExpression()
 	}
 
-	// Registra a variável na tabela de símbolos
-	if err := p.symbolTable.Declare(nameToken.Lexeme, SymbolInfo{
-		Name:      nameToken.Lexeme,
-		Type:      typeToken.Lexeme,
-		Category:  Variable,
-		DefinedAt: nameToken.Line,
-	}); err != nil {
-		p.addError(err.Error(), nameToken.Line, nameToken.Column)
-	}
-
 	return &VariableDeclaration{
-		Type:  typeToken.Lexeme,
+		Type:  typeName,
 		Name:  nameToken.Lexeme,
 		Value: value,
 		Token: nameToken,
 	}
 }
 
+// atStructVariableDeclaration indica se o identificador atual é o tipo de uma
+// declaração de variável, como em "Point p", "Point[] ps" ou "Point[3] ps".
+// Sem isso, "ps[3]" seria lido como acesso a um elemento
+func (p *Parser) atStructVariableDeclaration() bool {
+	n := 1
+	if p.peekTokenAt(n).Type == token.LBRACKET {
+		n++
+		if p.peekTokenAt(n).Type == token.NUMBER {
+			n++
+		}
+		if p.peekTokenAt(n).Type != token.RBRACKET {
+			return false
+		}
+		n++
+	}
+	return p.peekTokenAt(n).Type == token.IDENTIFIER
+}
+
+// parseStructDeclaration analisa struct Nome { tipo campo; ... }. Os campos
+// são separados por ';' ou por quebras de linha
+func (p *Parser) parseStructDeclaration() *StructDeclaration {
+	p.nextToken() // Pula 'struct'
+
+	if p.current.Type != token.IDENTIFIER {
+		p.addError("Esperado nome da struct", p.current.Line, p.current.Column)
+		return nil
+	}
+	decl := &StructDeclaration{Name: p.current.Lexeme, Token: p.current}
+	p.nextToken()
+
+	if p.current.Type != token.LBRACE {
+		p.addError(fmt.Sprintf("Esperado '{' após 'struct %s'", decl.Name), p.current.Line, p.current.Column)
+		return nil
+	}
+	p.nextToken() // Pula '{'
+
+	for p.current.Type != token.RBRACE && !p.AtEnd() {
+		if p.current.Type == token.SEMICOLON {
+			p.nextToken()
+			continue
+		}
+		if p.current.Type != token.TYPE && p.current.Type != token.IDENTIFIER {
+			p.addError("Esperado tipo do campo", p.current.Line, p.current.Column)
+			return nil
+		}
+		fieldType, ok := p.parseTypeName()
+		if !ok {
+			return nil
+		}
+		if p.current.Type != token.IDENTIFIER {
+			p.addError(fmt.Sprintf("Esperado nome do campo após tipo '%s'", fieldType),
+				p.current.Line, p.current.Column)
+			return nil
+		}
+		decl.Fields = append(decl.Fields, &VariableDeclaration{
+			Type:  fieldType,
+			Name:  p.current.Lexeme,
+			Token: p.current,
+		})
+		p.nextToken()
+	}
+
+	if p.current.Type != token.RBRACE {
+		p.addError(fmt.Sprintf("Esperado '}' para fechar a struct '%s'", decl.Name),
+			p.current.Line, p.current.Column)
+		return nil
+	}
+	p.nextToken() // Pula '}'
+
+	return decl
+}
+
+// parseTypeName lê um tipo, que pode ser um array: "int", "int[10]" (tamanho
+// fixo) ou "int[]" (tamanho definido em tempo de execução). O token atual deve
+// ser o TYPE ou o nome de uma struct
+func (p *Parser) parseTypeName() (string, bool) {
+	typeName := p.current.Lexeme
+	p.nextToken()
+
+	if p.current.Type != token.LBRACKET {
+		return typeName, true
+	}
+	p.nextToken() // Pula '['
+
+	if p.current.Type == token.RBRACKET {
+		p.nextToken()
+		return typeName + "[]", true
+	}
+
+	sizeToken := p.current
+	size, err := strconv.Atoi(sizeToken.Lexeme)
+	if sizeToken.Type != token.NUMBER || err != nil || size <= 0 {
+		p.addError("Tamanho do array deve ser um inteiro positivo", sizeToken.Line, sizeToken.Column)
+		return "", false
+	}
+	p.nextToken()
+
+	if p.current.Type != token.RBRACKET {
+		p.addError("Esperado ']' após tamanho do array", p.current.Line, p.current.Column)
+		return "", false
+	}
+	p.nextToken() // Pula ']'
+
+	return fmt.Sprintf("%s[%d]", typeName, size), true
+}
+
 func isEndOfDeclaration(tok token.Token) bool {
 	return tok.Type == token.SEMICOLON ||
 		tok.Type == token.EOF ||
@@ -562,7 +900,7 @@ func (p *Parser) parseFunctionDeclaration() *FunctionDeclaration {
 
 	if p.current.Type != token.IDENTIFIER {
 		p.addError("Expected function name", p.current.Line, p.current.Column)
-		return nil
+		return p.abandonFunction()
 	}
 
 	name := p.current.Lexeme
@@ -571,25 +909,27 @@ func (p *Parser) parseFunctionDeclaration() *FunctionDeclaration {
 	// Parênteses de abertura
 	if p.current.Type != token.LPAREN {
 		p.addError("Expected '(' after function name", p.current.Line, p.current.Column)
-		return nil
+		return p.abandonFunction()
 	}
 	p.nextToken() // Pula '('
 
 	var params []*VariableDeclaration
 	for p.current.Type != token.RPAREN && !p.AtEnd() {
 		// Tipo do parâmetro
-		if p.current.Type != token.TYPE {
+		if p.current.Type != token.TYPE && p.current.Type != token.IDENTIFIER {
 			p.addError("Expected parameter type", p.current.Line, p.current.Column)
-			return nil
+			return p.abandonFunction()
 		}
 
-		paramType := p.current.Lexeme
-		p.nextToken()
+		paramType, ok := p.parseTypeName()
+		if !ok {
+			return p.abandonFunction()
+		}
 
 		// Nome do parâmetro
 		if p.current.Type != token.IDENTIFIER {
 			p.addError("Expected parameter name", p.current.Line, p.current.Column)
-			return nil
+			return p.abandonFunction()
 		}
 
 		paramName := p.current.Lexeme
@@ -606,35 +946,34 @@ func (p *Parser) parseFunctionDeclaration() *FunctionDeclaration {
 		} else if p.current.Type != token.RPAREN {
 			p.addError(fmt.Sprintf("Expected ',' or ')', got '%s'", p.current.Lexeme),
 				p.current.Line, p.current.Column)
-			return nil
+			return p.abandonFunction()
 		}
 	}
 
 	if p.current.Type != token.RPAREN {
 		p.addError("Expected ')' after parameters", p.current.Line, p.current.Column)
-		return nil
+		return p.abandonFunction()
 	}
 	p.nextToken() // Pula ')'
 
 	// Tipo de retorno
-	if p.current.Type != token.TYPE {
+	if p.current.Type != token.TYPE && p.current.Type != token.IDENTIFIER {
 		p.addError("Expected return type", p.current.Line, p.current.Column)
-		return nil
+		return p.abandonFunction()
 	}
 
-	returnType := p.current.Lexeme
-	p.nextToken()
+	returnType, ok := p.parseTypeName()
+	if !ok {
+		return p.abandonFunction()
+	}
 
 	// Corpo da função
 	if p.current.Type != token.LBRACE {
 		p.addError("Expected '{' to start function body", p.current.Line, p.current.Column)
-		return nil
+		return p.abandonFunction()
 	}
 
 	body := p.parseBlock()
-	if body == nil {
-		return nil
-	}
 
 	return &FunctionDeclaration{
 		Name:       name,
@@ -645,12 +984,25 @@ func (p *Parser) parseFunctionDeclaration() *FunctionDeclaration {
 	}
 }
 
+// abandonFunction descarta o restante de um cabeçalho de função inválido.
+// O corpo ainda é analisado, para reportar seus erros, mas é descartado:
+// sem isso seus comandos seriam lidos como se estivessem fora da função
+func (p *Parser) abandonFunction() *FunctionDeclaration {
+	p.skipUntil(token.LBRACE, token.FUNC)
+	if p.current.Type == token.LBRACE {
+		p.parseBlock()
+	}
+	return nil
+}
+
 // parseReturnStatement processa declarações de retorno
 func (p *Parser) parseReturnStatement() *ReturnStatement {
-	stmt := &ReturnStatement{}
+	stmt := &ReturnStatement{Token: p.current}
 	p.nextToken() // Pula o 'return'
 
-	if p.current.Type != token.SEMICOLON {
+	// Sem ';' obrigatório, o valor precisa começar na mesma linha do return
+	if p.current.Type != token.SEMICOLON && p.current.Type != token.RBRACE &&
+		!p.AtEnd() && p.current.Line == stmt.Token.Line {
 		stmt.Value = p.parseExpression()
 	}
 
@@ -665,10 +1017,15 @@ func (p *Parser) parseCallExpression() *CallExpression {
 
 	p.nextToken() // Pula '('
 	for p.current.Type != token.RPAREN && !p.AtEnd() {
-		call.Arguments = append(call.Arguments, p.parseExpression())
-		if p.current.Type == token.COMMA {
-			p.nextToken()
+		arg := p.parseExpression()
+		if arg == nil {
+			return nil
+		}
+		call.Arguments = append(call.Arguments, arg)
+		if p.current.Type != token.COMMA {
+			break
 		}
+		p.nextToken()
 	}
 
 	if p.current.Type != token.RPAREN {
@@ -682,34 +1039,92 @@ func (p *Parser) parseCallExpression() *CallExpression {
 
 // peekToken retorna o próximo token sem consumi-lo
 func (p *Parser) peekToken() token.Token {
-	if p.pos < len(p.tokens)-1 {
-		return p.tokens[p.pos]
+	return p.peekTokenAt(1)
+}
+
+// peekTokenAt retorna o token n posições à frente do atual sem consumi-lo
+func (p *Parser) peekTokenAt(n int) token.Token {
+	if p.pos+n-1 < len(p.tokens)-1 {
+		return p.tokens[p.pos+n-1]
 	}
 	return token.Token{Type: token.EOF}
 }
 
-// Parse analisa uma sequência de declarações e retorna a AST completa
-// parser/parser.go
+// Parse analisa uma sequência de declarações e retorna a AST completa. Mesmo
+// com erros, a análise sempre chega ao fim do arquivo e retorna os comandos
+// que puderam ser reconstruídos
 func (p *Parser) Parse() []Statement {
-	// Processa todas as funções primeiro
-	var funcs []Statement
-	for !p.AtEnd() && p.current.Type == token.FUNC {
-		funcs = append(funcs, p.parseFunctionDeclaration())
+	var statements []Statement
+	for !p.AtEnd() {
+		if stmt := p.parseStatementWithRecovery(); stmt != nil {
+			statements = append(statements, stmt)
+		}
 	}
+	return statements
+}
 
-	// Processa outras declarações
+// parseStatementWithRecovery analisa um comando e, se ele tiver erros, entra
+// em modo pânico: descarta tokens até um ponto de sincronização. Também
+// garante que ao menos um token seja consumido, para que os laços de
+// comandos sempre terminem
+func (p *Parser) parseStatementWithRecovery() Statement {
+	startPos, startErrors := p.pos, len(p.Errors)
+
+	stmt := p.ParseStatement()
+	if len(p.Errors) > startErrors {
+		p.synchronize(p.Errors[startErrors].Line)
+	}
+	if p.pos == startPos {
+		p.Skip()
+	}
+	return stmt
+}
+
+// synchronize descarta tokens até um ponto seguro para retomar a análise:
+// logo após um ';', antes de um '}' ou de uma palavra-chave que inicia um
+// comando, ou no primeiro token de uma linha posterior à do erro (comandos
+// são separados por quebras de linha). Blocos { } encontrados no caminho são
+// pulados inteiros para não gerar erros em cascata com o seu conteúdo.
+func (p *Parser) synchronize(errorLine int) {
+	depth := 0
 	for !p.AtEnd() {
-		stmt := p.ParseStatement()
-		if stmt != nil {
-			funcs = append(funcs, stmt)
+		if depth == 0 && (p.atSyncPoint() || p.current.Line > errorLine) {
+			if p.current.Type == token.SEMICOLON {
+				p.nextToken()
+			}
+			return
+		}
+
+		switch p.current.Type {
+		case token.LBRACE:
+			depth++
+		case token.RBRACE:
+			depth--
 		}
+		p.nextToken()
+	}
+}
+
+// atSyncPoint indica se o token atual é um ponto de sincronização
+func (p *Parser) atSyncPoint() bool {
+	switch p.current.Type {
+	case token.SEMICOLON, token.RBRACE,
+		token.FUNC, token.STRUCT, token.TYPE, token.IF, token.WHILE, token.FOR,
+		token.RETURN, token.BREAK, token.CONTINUE, token.PRINT:
+		return true
 	}
-	return funcs
+	return false
 }
 
 // Função auxiliar para registrar erros
 // Em parser/parser.go
 func (p *Parser) addError(msg string, line int, column int) {
+	// Um erro por posição: os demais costumam ser consequência do primeiro
+	for _, err := range p.Errors {
+		if err.Line == line && err.Column == column {
+			return
+		}
+	}
 	p.Errors = append(p.Errors, ParseError{
 		Message: msg,
 		Line:    line,
@@ -718,6 +1133,17 @@ func (p *Parser) addError(msg string, line int, column int) {
 	})
 }
 
+// addIllegalTokenError reporta um erro léxico. O lexer guarda a mensagem no
+// lexema do token ILLEGAL, que é consumido
+func (p *Parser) addIllegalTokenError() {
+	before := len(p.Errors)
+	p.addError(p.current.Lexeme, p.current.Line, p.current.Column)
+	if len(p.Errors) > before {
+		p.Errors[before].Token = "" // O lexema não é um trecho do código
+	}
+	p.nextToken()
+}
+
 func (p *Parser) skipUntil(stopTokens ...token.TokenType) {
 	for !p.AtEnd() {
 		for _, stop := range stopTokens {
@@ -730,30 +1156,16 @@ func (p *Parser) skipUntil(stopTokens ...token.TokenType) {
 }
 func (p *Parser) parsePrintStatement() Statement {
 	printToken := p.current
-	p.nextToken() // Pula 'print'
-
-	if p.current.Type != token.LPAREN {
+	if p.peekToken().Type != token.LPAREN {
 		p.addError("Esperado '(' após print", printToken.Line, printToken.Column)
+		p.nextToken() // Pula 'print'
 		return nil
 	}
-	p.nextToken() // Pula '('
 
-	expr := p.parseExpression()
-	if expr == nil {
+	// print(a, b, c) tem a mesma forma de uma chamada de função
+	call := p.parseCallExpression()
+	if call == nil {
 		return nil
 	}
-
-	if p.current.Type != token.RPAREN {
-		p.addError("Esperado ')' após expressão do print", p.current.Line, p.current.Column)
-		return nil
-	}
-	p.nextToken() // Pula ')'
-
-	return &ExpressionStatement{
-		Expression: &CallExpression{
-			FunctionName: "print",
-			Arguments:    []Expression{expr},
-			Token:        printToken,
-		},
-	}
+	return &ExpressionStatement{Expression: call}
 }
@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"simple-compiler/lexer"
+	"testing"
+	"time"
+)
+
+func parseSource(t *testing.T, src string) ([]Statement, []ParseError) {
+	t.Helper()
+	p := New(lexer.Tokenize(src))
+	return p.Parse(), p.Errors
+}
+
+func TestParseSemErros(t *testing.T) {
+	src := `int x = 1
+float y = 2.5
+func soma(int a, int b) int {
+	return a + b
+}
+while (x < 10) {
+	x = x + 1
+}
+print(soma(x, 2))
+`
+	statements, errs := parseSource(t, src)
+	if len(errs) != 0 {
+		t.Fatalf("erros inesperados: %v", errs)
+	}
+	if len(statements) != 5 {
+		t.Fatalf("esperava 5 declarações, obteve %d", len(statements))
+	}
+}
+
+// Após um erro o parser deve se sincronizar e continuar lendo as declarações
+// seguintes, sem gerar uma cascata de erros
+func TestParseRecuperacaoDeErros(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       string
+		maxErrors int
+		lastDecl  string // Nome da última declaração de variável esperada
+	}{
+		{
+			name:      "expressão ausente",
+			src:       "int x =\nint y = 2\n",
+			maxErrors: 1,
+			lastDecl:  "y",
+		},
+		{
+			name:      "parêntese não fechado",
+			src:       "int x = (1 + 2\nint y = 2\n",
+			maxErrors: 1,
+			lastDecl:  "y",
+		},
+		{
+			name:      "erro dentro de bloco",
+			src:       "func f() void {\n\tint a = * 2\n\tint b = 3\n}\nint z = 4\n",
+			maxErrors: 1,
+			lastDecl:  "z",
+		},
+		{
+			name:      "sincroniza na palavra-chave",
+			src:       "x = = 3 while (true) { break }\nint w = 1\n",
+			maxErrors: 1,
+			lastDecl:  "w",
+		},
+		{
+			name:      "vários erros independentes",
+			src:       "int a = *\nint b = )\nint c = 3\n",
+			maxErrors: 2,
+			lastDecl:  "c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements, errs := parseSource(t, tt.src)
+			if len(errs) == 0 {
+				t.Fatalf("esperava ao menos um erro")
+			}
+			if len(errs) > tt.maxErrors {
+				t.Errorf("esperava no máximo %d erros, obteve %d: %v", tt.maxErrors, len(errs), errs)
+			}
+			if len(statements) == 0 {
+				t.Fatalf("nenhuma declaração foi recuperada")
+			}
+			last, ok := statements[len(statements)-1].(*VariableDeclaration)
+			if !ok || last.Name != tt.lastDecl {
+				t.Errorf("esperava que a última declaração fosse %q, obteve %v", tt.lastDecl, statements[len(statements)-1])
+			}
+		})
+	}
+}
+
+// Entradas truncadas ou só com lixo não podem travar o parser
+func TestParseTermina(t *testing.T) {
+	sources := []string{
+		"",
+		"{",
+		"}",
+		"func",
+		"func f(",
+		"if (x",
+		"int[",
+		"))))",
+		"struct S { int a",
+		"for (int i = 0; i <",
+		"print(\"a\"",
+		"@ # $",
+	}
+
+	for _, src := range sources {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			parseSource(t, src)
+		}()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("o parser não terminou para a entrada %q", src)
+		}
+	}
+}
@@ -16,30 +16,33 @@ const (
 
 // Tipo para informações do símbolo
 type SymbolInfo struct {
-	Name      string
-	Category  SymbolCategory
-	Type      string      // Tipo do símbolo (int, float, etc)
-	Value     interface{} // Valor atual (opcional)
-	DefinedAt int         // Linha onde foi definido (para mensagens de erro)
+	Name        string
+	Category    SymbolCategory
+	Type        string      // Tipo do símbolo (int, float, etc)
+	Value       interface{} // Valor atual (opcional)
+	DefinedAt   int         // Linha onde foi definido (para mensagens de erro)
+	DefinedCol  int         // Coluna onde foi definido
+	Declaration Statement   // Nó que declarou o símbolo (VariableDeclaration ou FunctionDeclaration)
 }
 
-// Tabela de símbolos com escopos aninhados
+// Tabela de símbolos com escopos aninhados. Guarda ponteiros para que os nós
+// da AST possam apontar para o mesmo símbolo
 type SymbolTable struct {
-	scopes []map[string]SymbolInfo
+	scopes []map[string]*SymbolInfo
 }
 
 // Cria nova tabela de símbolos com escopo global
 func NewSymbolTable() *SymbolTable {
 	return &SymbolTable{
-		scopes: []map[string]SymbolInfo{
-			make(map[string]SymbolInfo), // Escopo global
+		scopes: []map[string]*SymbolInfo{
+			make(map[string]*SymbolInfo), // Escopo global
 		},
 	}
 }
 
 // Entra em um novo escopo
 func (st *SymbolTable) PushScope() {
-	st.scopes = append(st.scopes, make(map[string]SymbolInfo))
+	st.scopes = append(st.scopes, make(map[string]*SymbolInfo))
 }
 
 // Sai do escopo atual
@@ -51,7 +54,7 @@ func (st *SymbolTable) PopScope() {
 }
 
 // Declara um novo símbolo no escopo atual
-func (st *SymbolTable) Declare(name string, info SymbolInfo) error {
+func (st *SymbolTable) Declare(name string, info *SymbolInfo) error {
     currentScope := st.scopes[len(st.scopes)-1]
     
     // Permite sobrecarregar funções (se for função)
@@ -68,13 +71,13 @@ func (st *SymbolTable) Declare(name string, info SymbolInfo) error {
     return nil
 }
 
-func (st *SymbolTable) Resolve(name string) (SymbolInfo, bool) {
+func (st *SymbolTable) Resolve(name string) (*SymbolInfo, bool) {
     for i := len(st.scopes) - 1; i >= 0; i-- {
         if info, exists := st.scopes[i][name]; exists {
             return info, true
         }
     }
-    return SymbolInfo{}, false
+    return nil, false
 }
 
 // Atualiza o valor de um símbolo existente
@@ -89,7 +92,6 @@ func (st *SymbolTable) Update(name string, value interface{}) error {
 			}
 			// Atualiza apenas o valor, mantendo outras informações
 			info.Value = value
-			st.scopes[i][name] = info
 			return nil
 		}
 	}
@@ -0,0 +1,258 @@
+package semantic
+
+import (
+	"fmt"
+	"simple-compiler/parser"
+)
+
+// Resolver liga cada uso de um nome (identificadores, atribuições e
+// chamadas) ao símbolo da sua declaração, preenchendo o campo Symbol dos nós.
+// Respeita os escopos de funções, blocos e laços. Funções de nível superior e
+// variáveis globais ficam visíveis em todo o arquivo, independente da ordem
+// em que aparecem.
+type Resolver struct {
+	reporter
+	ast    []parser.Statement
+	scopes *parser.SymbolTable
+}
+
+func NewResolver(ast []parser.Statement) *Resolver {
+	return &Resolver{
+		reporter: reporter{errors: make([]SemanticError, 0)},
+		ast:      ast,
+		scopes:   parser.NewSymbolTable(),
+	}
+}
+
+func (r *Resolver) Resolve() []SemanticError {
+	r.declareFunctions()
+
+	// Globais são declaradas antes dos corpos das funções que as usam
+	for _, stmt := range r.ast {
+		if decl, ok := stmt.(*parser.VariableDeclaration); ok {
+			r.resolveVariableDecl(decl)
+		}
+	}
+
+	for _, stmt := range r.ast {
+		switch s := stmt.(type) {
+		case *parser.VariableDeclaration, *parser.StructDeclaration:
+			continue
+		case *parser.FunctionDeclaration:
+			r.resolveFunction(s)
+		default:
+			r.resolveStatement(stmt)
+		}
+	}
+	return r.errors
+}
+
+// declareFunctions registra no escopo global todas as funções de nível superior
+func (r *Resolver) declareFunctions() {
+	for _, stmt := range r.ast {
+		fd, ok := stmt.(*parser.FunctionDeclaration)
+		if !ok {
+			continue
+		}
+
+		if previous, exists := r.scopes.Resolve(fd.Name); exists {
+			r.addError(fmt.Sprintf("Função '%s' já declarada", fd.Name), fd.Token)
+			r.addNote("declaração anterior aqui", previous.Declaration.GetToken())
+			continue
+		}
+		if isBuiltinFunction(fd.Name) {
+			r.addError(fmt.Sprintf("Função '%s' já declarada", fd.Name), fd.Token)
+			continue
+		}
+
+		r.scopes.Declare(fd.Name, &parser.SymbolInfo{
+			Name:        fd.Name,
+			Type:        fd.ReturnType,
+			Category:    parser.Function,
+			DefinedAt:   fd.Token.Line,
+			DefinedCol:  fd.Token.Column,
+			Declaration: fd,
+		})
+	}
+}
+
+func (r *Resolver) resolveFunction(fd *parser.FunctionDeclaration) {
+	// Parâmetros e corpo compartilham o escopo da função
+	r.scopes.PushScope()
+	defer r.scopes.PopScope()
+
+	for _, param := range fd.Parameters {
+		r.declareVariable(param)
+	}
+	for _, stmt := range fd.Body {
+		r.resolveStatement(stmt)
+	}
+}
+
+func (r *Resolver) resolveStatement(stmt parser.Statement) {
+	switch s := stmt.(type) {
+	case *parser.VariableDeclaration:
+		r.resolveVariableDecl(s)
+	case *parser.AssignmentStatement:
+		r.resolveAssignment(s)
+	case *parser.IndexAssignmentStatement:
+		r.resolveExpression(s.Value)
+		r.resolveExpression(s.Target)
+	case *parser.FieldAssignmentStatement:
+		r.resolveExpression(s.Value)
+		r.resolveExpression(s.Target)
+	case *parser.IfStatement:
+		r.resolveExpression(s.Condition)
+		r.resolveBlock(s.Body)
+		r.resolveBlock(s.ElseBody)
+	case *parser.WhileStatement:
+		r.resolveExpression(s.Condition)
+		r.resolveBlock(s.Body)
+	case *parser.ForStatement:
+		// A variável declarada na inicialização só existe dentro do for
+		r.scopes.PushScope()
+		if s.Init != nil {
+			r.resolveStatement(s.Init)
+		}
+		if s.Condition != nil {
+			r.resolveExpression(s.Condition)
+		}
+		if s.Update != nil {
+			r.resolveStatement(s.Update)
+		}
+		r.resolveBlock(s.Body)
+		r.scopes.PopScope()
+	case *parser.BlockStatement:
+		r.resolveBlock(s)
+	case *parser.ReturnStatement:
+		if s.Value != nil {
+			r.resolveExpression(s.Value)
+		}
+	case *parser.ExpressionStatement:
+		r.resolveExpression(s.Expression)
+	case *parser.FunctionDeclaration:
+		// O gerador de código não suporta funções aninhadas (closures)
+		r.addError(fmt.Sprintf("Função '%s' deve ser declarada no nível superior", s.Name), s.Token)
+	case *parser.StructDeclaration:
+		r.addError(fmt.Sprintf("Struct '%s' deve ser declarada no nível superior", s.Name), s.Token)
+	}
+}
+
+func (r *Resolver) resolveBlock(block *parser.BlockStatement) {
+	if block == nil {
+		return
+	}
+	r.scopes.PushScope()
+	for _, stmt := range block.Statements {
+		r.resolveStatement(stmt)
+	}
+	r.scopes.PopScope()
+}
+
+func (r *Resolver) resolveVariableDecl(decl *parser.VariableDeclaration) {
+	// O inicializador é resolvido antes de declarar a variável, para que ela
+	// não seja visível nele
+	if decl.Value != nil {
+		r.resolveExpression(decl.Value)
+	}
+	r.declareVariable(decl)
+}
+
+// declareVariable registra uma variável ou parâmetro no escopo atual
+func (r *Resolver) declareVariable(decl *parser.VariableDeclaration) {
+	if r.scopes.ExistsInCurrentScope(decl.Name) {
+		previous, _ := r.scopes.Resolve(decl.Name)
+		r.addError(fmt.Sprintf("Variável '%s' já declarada neste escopo", decl.Name), decl.Token)
+		r.addNote("declaração anterior aqui", definitionToken(previous))
+		return
+	}
+
+	r.scopes.Declare(decl.Name, &parser.SymbolInfo{
+		Name:        decl.Name,
+		Type:        decl.Type,
+		Category:    parser.Variable,
+		DefinedAt:   decl.Token.Line,
+		DefinedCol:  decl.Token.Column,
+		Declaration: decl,
+	})
+}
+
+func (r *Resolver) resolveAssignment(assign *parser.AssignmentStatement) {
+	r.resolveExpression(assign.Value)
+
+	sym, exists := r.scopes.Resolve(assign.Name)
+	if !exists {
+		r.addError(fmt.Sprintf("Variável '%s' não declarada", assign.Name), assign.NameToken)
+		return
+	}
+	if sym.Category == parser.Function {
+		r.addError(fmt.Sprintf("'%s' é uma função e não pode receber valores", assign.Name), assign.NameToken)
+		r.addNote("função declarada aqui", definitionToken(sym))
+		return
+	}
+	assign.Symbol = sym
+}
+
+func (r *Resolver) resolveExpression(expr parser.Expression) {
+	switch e := expr.(type) {
+	case *parser.Identifier:
+		// Variáveis podem ter o nome de um builtin (ex: len) e o sombreiam
+		sym, exists := r.scopes.Resolve(e.Name)
+		if !exists {
+			if isBuiltinFunction(e.Name) {
+				r.addError(fmt.Sprintf("'%s' é uma função e não pode ser usada como valor", e.Name), e.Token)
+				return
+			}
+			r.addError(fmt.Sprintf("Identificador não declarado: %s", e.Name), e.Token)
+			return
+		}
+		if sym.Category == parser.Function {
+			r.addError(fmt.Sprintf("'%s' é uma função e não pode ser usada como valor", e.Name), e.Token)
+			r.addNote("função declarada aqui", definitionToken(sym))
+			return
+		}
+		e.Symbol = sym
+	case *parser.BinaryExpression:
+		r.resolveExpression(e.Left)
+		r.resolveExpression(e.Right)
+	case *parser.UnaryExpression:
+		r.resolveExpression(e.Right)
+	case *parser.CallExpression:
+		r.resolveCall(e)
+	case *parser.IndexExpression:
+		r.resolveExpression(e.Array)
+		r.resolveExpression(e.Index)
+	case *parser.NewArrayExpression:
+		r.resolveExpression(e.Size)
+	case *parser.CastExpression:
+		r.resolveExpression(e.Value)
+	case *parser.MemberExpression:
+		r.resolveExpression(e.Object)
+	case *parser.StructLiteral:
+		for _, field := range e.Fields {
+			r.resolveExpression(field.Value)
+		}
+	}
+}
+
+func (r *Resolver) resolveCall(call *parser.CallExpression) {
+	for _, arg := range call.Arguments {
+		r.resolveExpression(arg)
+	}
+	if isBuiltinFunction(call.FunctionName) {
+		return
+	}
+
+	sym, exists := r.scopes.Resolve(call.FunctionName)
+	if !exists {
+		r.addError(fmt.Sprintf("Função '%s' não declarada", call.FunctionName), call.Token)
+		return
+	}
+	if sym.Category != parser.Function {
+		r.addError(fmt.Sprintf("'%s' não é uma função", call.FunctionName), call.Token)
+		r.addNote(fmt.Sprintf("'%s' declarada aqui como %s", call.FunctionName, sym.Type),
+			definitionToken(sym))
+		return
+	}
+	call.Symbol = sym
+}
@@ -0,0 +1,54 @@
+package semantic
+
+import (
+	"testing"
+)
+
+func TestResolverOrdemDasDeclaracoes(t *testing.T) {
+	runSemanticCases(t, []semanticCase{
+		{"função declarada depois do uso", "func main() void {\n\tprint(dobro(2))\n}\nfunc dobro(int x) int {\n\treturn x * 2\n}\n", ""},
+		{"global declarada depois da função", "func f() int {\n\treturn total\n}\nint total = 3\nprint(f())\n", ""},
+		{"recursão mútua", "func par(int n) bool {\n\tif (n == 0) {\n\t\treturn true\n\t}\n\treturn impar(n - 1)\n}\nfunc impar(int n) bool {\n\tif (n == 0) {\n\t\treturn false\n\t}\n\treturn par(n - 1)\n}\nprint(par(4))\n", ""},
+		{"local usada antes da declaração", "func f() int {\n\tint y = x\n\tint x = 1\n\treturn y\n}\n", "Identificador não declarado: x"},
+		{"inicializador não vê a própria variável", "func f() int {\n\tint x = x + 1\n\treturn x\n}\n", "Identificador não declarado: x"},
+	})
+}
+
+func TestResolverEscopos(t *testing.T) {
+	runSemanticCases(t, []semanticCase{
+		{"sombreamento em bloco interno", "int x = 1\n{\n\tstring x = \"a\"\n\tprint(x)\n}\nprint(x + 1)\n", ""},
+		{"variável de bloco fora do bloco", "{\n\tint y = 1\n}\nprint(y)\n", "Identificador não declarado: y"},
+		{"variável do for fora do laço", "for (int i = 0; i < 3; i = i + 1) {\n}\nprint(i)\n", "Identificador não declarado: i"},
+		{"parâmetro fora da função", "func f(int a) int {\n\treturn a\n}\nprint(a)\n", "Identificador não declarado: a"},
+		{"redeclaração no mesmo escopo", "int x = 1\nint x = 2\n", "Variável 'x' já declarada neste escopo"},
+		{"parâmetro repetido", "func f(int a, int a) int {\n\treturn a\n}\n", "Variável 'a' já declarada neste escopo"},
+		{"atribuição a variável não declarada", "y = 3\n", "Variável 'y' não declarada"},
+	})
+}
+
+func TestResolverFuncoes(t *testing.T) {
+	runSemanticCases(t, []semanticCase{
+		{"função usada como valor", "func soma(int a, int b) int {\n\treturn a + b\n}\nint y = soma\n", "'soma' é uma função e não pode ser usada como valor"},
+		{"builtin usado como valor", "int n = len\n", "'len' é uma função e não pode ser usada como valor"},
+		{"atribuição a uma função", "func f() void {\n}\nf = 3\n", "'f' é uma função e não pode receber valores"},
+		{"chamada de variável", "int g = 1\nprint(g())\n", "'g' não é uma função"},
+		{"função não declarada", "print(nada())\n", "Função 'nada' não declarada"},
+		{"função repetida", "func f() void {\n}\nfunc f() void {\n}\n", "Função 'f' já declarada"},
+		{"função com nome de builtin", "func len(int x) int {\n\treturn x\n}\n", "Função 'len' já declarada"},
+		{"função aninhada", "func f() void {\n\tfunc g() void {\n\t}\n}\n", "Função 'g' deve ser declarada no nível superior"},
+	})
+}
+
+// Os erros do resolvedor apontam para o uso e anotam a declaração
+func TestResolverNotas(t *testing.T) {
+	errs := analyze(t, "func soma() int {\n\treturn 1\n}\nint y = soma\n")
+	if len(errs) != 1 {
+		t.Fatalf("esperava 1 erro, obteve %v", errs)
+	}
+	if errs[0].Line != 4 || errs[0].Column != 9 {
+		t.Errorf("erro em %d:%d, esperava 4:9", errs[0].Line, errs[0].Column)
+	}
+	if len(errs[0].Notes) != 1 || errs[0].Notes[0].Line != 1 {
+		t.Errorf("esperava a nota na declaração da linha 1, obteve %+v", errs[0].Notes)
+	}
+}
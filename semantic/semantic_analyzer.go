@@ -2,153 +2,510 @@ package semantic
 
 import (
 	"fmt"
+	"math"
+	"simple-compiler/format"
 	"simple-compiler/parser"
+	"simple-compiler/token"
+	"simple-compiler/types"
 )
 
+// Analyzer verifica os tipos do programa. Os nomes são resolvidos antes pelo
+// Resolver, então os nós já chegam ligados às suas declarações
 type Analyzer struct {
+	reporter
 	ast         []parser.Statement
-	symbolTable *parser.SymbolTable
-	errors      []SemanticError
+	info        *types.Info                 // Tipos registrados para a geração de código
+	types       *types.Table                // Structs declaradas no arquivo
+	currentFunc *parser.FunctionDeclaration // Função sendo analisada (nil no nível superior)
+	loopDepth   int                         // Quantidade de laços envolvendo o comando atual
 }
 
 type SemanticError struct {
 	Message string
 	Line    int
+	Column  int
 	Token   string
+	Notes   []Note // Informações extras, como onde um símbolo foi declarado
+}
+
+// Note aponta para outro trecho do código relacionado a um erro
+type Note struct {
+	Message string
+	Line    int
+	Column  int
+	Token   string
+}
+
+// reporter acumula os erros das passagens da análise semântica
+type reporter struct {
+	errors []SemanticError
 }
 
 func New(ast []parser.Statement) *Analyzer {
+	info := types.NewInfo()
 	return &Analyzer{
-		ast:         ast,
-		symbolTable: parser.NewSymbolTable(),
-		errors:      make([]SemanticError, 0),
+		reporter: reporter{errors: make([]SemanticError, 0)},
+		ast:      ast,
+		info:     info,
+		types:    info.Structs,
 	}
 }
 
+// Info retorna os tipos encontrados pela análise. Só é completo quando
+// Analyze não encontrou erros
+func (a *Analyzer) Info() *types.Info {
+	return a.info
+}
+
 func (a *Analyzer) Analyze() []SemanticError {
+	// Liga os nomes às declarações antes de verificar os tipos
+	a.errors = append(a.errors, NewResolver(a.ast).Resolve()...)
+	a.declareStructs()
+
+	hasMain := false
 	for _, stmt := range a.ast {
+		if fd, ok := stmt.(*parser.FunctionDeclaration); ok && fd.Name == "main" {
+			hasMain = true
+		}
+	}
+
+	for _, stmt := range a.ast {
+		switch stmt.(type) {
+		case *parser.VariableDeclaration, *parser.FunctionDeclaration, *parser.StructDeclaration:
+		default:
+			// Sem main, os comandos de nível superior formam um main implícito
+			if hasMain {
+				a.addError("Comandos no nível superior não são permitidos quando 'main' é declarada",
+					stmt.GetToken())
+			}
+		}
 		a.checkStatement(stmt)
 	}
 	return a.errors
 }
 
-func (a *Analyzer) addError(msg string, line int, token string) {
-	a.errors = append(a.errors, SemanticError{
+// declareStructs registra na tabela de tipos as structs de nível superior,
+// que podem ser usadas antes da própria declaração
+func (a *Analyzer) declareStructs() {
+	for _, sd := range a.types.DeclareStructs(a.ast) {
+		previous, _ := a.types.Lookup(sd.Name)
+		a.addError(fmt.Sprintf("Struct '%s' já declarada", sd.Name), sd.Token)
+		a.addNote("declaração anterior aqui", previous.Decl.Token)
+	}
+}
+
+func (r *reporter) addError(msg string, tok token.Token) {
+	r.errors = append(r.errors, SemanticError{
+		Message: msg,
+		Line:    tok.Line,
+		Column:  tok.Column,
+		Token:   tok.Lexeme,
+	})
+}
+
+// addNote anexa uma nota ao último erro registrado
+func (r *reporter) addNote(msg string, tok token.Token) {
+	last := &r.errors[len(r.errors)-1]
+	last.Notes = append(last.Notes, Note{
 		Message: msg,
-		Line:    line,
-		Token:   token,
+		Line:    tok.Line,
+		Column:  tok.Column,
+		Token:   tok.Lexeme,
 	})
 }
 
+// definitionToken monta a posição onde um símbolo foi declarado
+func definitionToken(sym *parser.SymbolInfo) token.Token {
+	return token.Token{Line: sym.DefinedAt, Column: sym.DefinedCol, Lexeme: sym.Name}
+}
+
 func (a *Analyzer) checkVariableDecl(decl *parser.VariableDeclaration) {
 	// Verificação de tipo
-	switch decl.Type {
-	case "int", "float", "string", "bool":
-		// Tipos válidos
-	default:
-		a.addError(fmt.Sprintf("Tipo desconhecido: %s", decl.Type),
-			decl.GetToken().Line, decl.GetToken().Lexeme)
-	}
-	if a.symbolTable.ExistsInCurrentScope(decl.Name) {
-		a.addError(fmt.Sprintf("Variável '%s' já declarada neste escopo", decl.Name),
-			decl.Token.Line, decl.Token.Lexeme)
-	}
+	declType := a.resolveType(decl.Type, decl.GetToken())
 
-	// Registra a variável
-	a.symbolTable.Declare(decl.Name, parser.SymbolInfo{
-		Type:      decl.Type,
-		Category:  parser.Variable,
-		DefinedAt: decl.Token.Line,
-	})
+	// Arrays de tamanho fixo começam zerados e são preenchidos elemento a elemento
+	if array, ok := declType.(*types.Array); ok && array.IsFixed() && decl.Value != nil {
+		a.checkExpression(decl.Value)
+		a.addError(fmt.Sprintf("Array '%s' de tamanho fixo não aceita inicializador; use %s[] para receber outro array",
+			decl.Name, array.Elem), decl.Token)
+		return
+	}
 
-	// Verifica a expressão de inicialização
 	if decl.Value != nil {
 		exprType := a.checkExpression(decl.Value)
-		if exprType != "" && !a.isCompatible(decl.Type, exprType) {
+		if !a.assignTo(decl.Value, exprType, declType) {
 			a.addError(fmt.Sprintf("Tipo incompatível: não é possível atribuir %s a %s",
-				exprType, decl.Type), decl.Token.Line, decl.Token.Lexeme)
+				exprType, decl.Type), decl.Token)
+			a.suggestCast(decl.Value, exprType, declType)
 		}
 	}
 }
 
 func (a *Analyzer) checkAssignment(assign *parser.AssignmentStatement) {
-	sym, exists := a.symbolTable.Resolve(assign.Name)
-	if !exists {
-		a.addError(fmt.Sprintf("Variável '%s' não declarada", assign.Name),
-			assign.Token.Line, assign.Token.Lexeme)
+	exprType := a.checkExpression(assign.Value)
+
+	// Sem símbolo, o resolvedor já reportou o nome
+	sym := assign.Symbol
+	if sym == nil {
+		return
+	}
+	declType := a.symbolType(sym)
+	if array, ok := declType.(*types.Array); ok && array.IsFixed() {
+		a.addError(fmt.Sprintf("Array '%s' de tamanho fixo não pode receber outro array; atribua os elementos",
+			assign.Name), assign.NameToken)
+		a.addNote(fmt.Sprintf("variável '%s' declarada aqui como %s", assign.Name, sym.Type),
+			definitionToken(sym))
 		return
 	}
+	if !a.assignTo(assign.Value, exprType, declType) {
+		a.addError(fmt.Sprintf("Tipo incompatível em atribuição: %s = %s",
+			sym.Type, exprType), assign.NameToken)
+		a.addNote(fmt.Sprintf("variável '%s' declarada aqui como %s", assign.Name, sym.Type),
+			definitionToken(sym))
+		a.suggestCast(assign.Value, exprType, declType)
+		return
+	}
+	if a.isGlobal(sym) {
+		a.checkArrayEscape(assign.Value, fmt.Sprintf("guardado na variável global '%s'", assign.Name))
+	}
+}
 
+// checkIndexAssignment verifica xs[i] = valor contra o tipo dos elementos
+func (a *Analyzer) checkIndexAssignment(assign *parser.IndexAssignmentStatement) {
+	elemType := a.checkExpression(assign.Target)
 	exprType := a.checkExpression(assign.Value)
-	if exprType != "" && !a.isCompatible(sym.Type, exprType) {
+	if !a.assignTo(assign.Value, exprType, elemType) {
 		a.addError(fmt.Sprintf("Tipo incompatível em atribuição: %s = %s",
-			sym.Type, exprType), assign.Token.Line, assign.Token.Lexeme)
+			elemType, exprType), assign.Token)
+		a.suggestCast(assign.Value, exprType, elemType)
+	}
+}
+
+// checkFieldAssignment verifica p.x = valor contra o tipo do campo
+func (a *Analyzer) checkFieldAssignment(assign *parser.FieldAssignmentStatement) {
+	fieldType := a.checkExpression(assign.Target)
+	exprType := a.checkExpression(assign.Value)
+	if !a.assignTo(assign.Value, exprType, fieldType) {
+		a.addError(fmt.Sprintf("Tipo incompatível em atribuição: %s = %s",
+			fieldType, exprType), assign.Token)
+		a.suggestCast(assign.Value, exprType, fieldType)
+		return
 	}
+	a.checkArrayEscape(assign.Value, "guardado em um campo de struct")
 }
 
-func (a *Analyzer) isCompatible(targetType, exprType string) bool {
-	compatibility := map[string]map[string]bool{
-		"int":    {"int": true, "float": true},
-		"float":  {"float": true, "int": true},
-		"string": {"string": true},
-		"bool":   {"bool": true},
+// checkStructDecl verifica os campos de uma struct: nomes únicos, tipos
+// conhecidos e nenhuma struct contendo a si mesma, o que teria tamanho infinito
+func (a *Analyzer) checkStructDecl(named *types.Named) {
+	seen := make(map[string]*types.Field)
+	for _, field := range named.Fields {
+		if previous, exists := seen[field.Name]; exists {
+			a.addError(fmt.Sprintf("Campo '%s' já declarado na struct '%s'", field.Name, named.Name),
+				field.Decl.Token)
+			a.addNote("declaração anterior aqui", previous.Decl.Token)
+			continue
+		}
+		seen[field.Name] = field
+
+		array, isArray := field.Type.(*types.Array)
+		switch {
+		case field.Type == nil || !types.IsValue(field.Type):
+			a.addError(fmt.Sprintf("Tipo desconhecido: %s", field.Decl.Type), field.Decl.Token)
+		case isArray && array.IsFixed():
+			a.addError(fmt.Sprintf("Campo '%s' não pode ser um array de tamanho fixo; use %s[]",
+				field.Name, array.Elem), field.Decl.Token)
+		case containsStruct(field.Type, named, make(map[*types.Named]bool)):
+			a.addError(fmt.Sprintf("Struct '%s' não pode conter a si mesma (campo '%s')",
+				named.Name, field.Name), field.Decl.Token)
+		}
 	}
+}
 
-	if rules, ok := compatibility[targetType]; ok {
-		return rules[exprType]
+// containsStruct indica se um valor do tipo informado guarda, direta ou
+// indiretamente, a struct target. Arrays T[] não contam: guardam um ponteiro
+func containsStruct(t types.Type, target *types.Named, visited map[*types.Named]bool) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	if named == target {
+		return true
+	}
+	if visited[named] {
+		return false
+	}
+	visited[named] = true
+	for _, field := range named.Fields {
+		if containsStruct(field.Type, target, visited) {
+			return true
+		}
 	}
 	return false
 }
 
-func (a *Analyzer) isNumeric(typeName string) bool {
-	return typeName == "int" || typeName == "float"
+// assignTo verifica se o valor da expressão pode ser guardado em target e
+// registra a conversão implícita necessária (ex: int guardado em um float).
+// Tipos desconhecidos (nil) já foram reportados e não geram novos erros
+func (a *Analyzer) assignTo(expr parser.Expression, value, target types.Type) bool {
+	if value == nil || target == nil {
+		return true
+	}
+	if !types.AssignableTo(value, target) {
+		return false
+	}
+	a.convert(expr, value, target)
+	return true
 }
 
-func (a *Analyzer) resultType(leftType, rightType string) string {
-	if leftType == "float" || rightType == "float" {
-		return "float"
+// convert registra que o valor numérico da expressão deve ser convertido para
+// target antes de ser usado
+func (a *Analyzer) convert(expr parser.Expression, value, target types.Type) {
+	if !types.Identical(value, target) && types.ConvertibleTo(value, target) {
+		a.info.Conversions[expr] = target
 	}
-	return "int"
 }
 
+// checkArrayEscape reporta um array de tamanho fixo local (variável ou
+// parâmetro) guardado onde vive mais que a função: no retorno, em uma global
+// ou em um campo de struct. Usado como T[], ele aponta para a pilha da função,
+// que deixa de existir no return
+func (a *Analyzer) checkArrayEscape(expr parser.Expression, where string) {
+	ident, ok := expr.(*parser.Identifier)
+	if !ok || ident.Symbol == nil || a.currentFunc == nil || a.isGlobal(ident.Symbol) {
+		return
+	}
+	array, ok := a.symbolType(ident.Symbol).(*types.Array)
+	if !ok || !array.IsFixed() {
+		return
+	}
+	a.addError(fmt.Sprintf("Array local '%s' não pode ser %s: ele deixa de existir quando '%s' retorna",
+		ident.Name, where, a.currentFunc.Name), ident.Token)
+	a.addNote(fmt.Sprintf("declarado aqui como %s; use new %s[%d] para criar o array no heap",
+		array, array.Elem, array.Len), definitionToken(ident.Symbol))
+}
 
-func (a *Analyzer) checkExpression(expr parser.Expression) string {
+// isGlobal indica se o símbolo é uma variável declarada no nível superior
+func (a *Analyzer) isGlobal(sym *parser.SymbolInfo) bool {
+	for _, stmt := range a.ast {
+		if stmt == sym.Declaration {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestCast anexa ao último erro a conversão explícita que o corrige, quando
+// há uma (ex: float guardado em um int)
+func (a *Analyzer) suggestCast(expr parser.Expression, value, target types.Type) {
+	if types.ConvertibleTo(value, target) {
+		a.addNote(fmt.Sprintf("use %s(...) para converter o valor explicitamente", target),
+			expr.GetToken())
+	}
+}
+
+// resolveType traduz um nome de tipo usado em uma declaração, reportando
+// tipos desconhecidos. Retorna nil nesse caso
+func (a *Analyzer) resolveType(name string, tok token.Token) types.Type {
+	t, ok := a.types.Resolve(name)
+	if !ok || !types.IsValue(t) {
+		a.addError(fmt.Sprintf("Tipo desconhecido: %s", name), tok)
+		return nil
+	}
+	return t
+}
+
+// symbolType retorna o tipo de uma variável ou parâmetro. Tipos desconhecidos
+// já foram reportados na declaração
+func (a *Analyzer) symbolType(sym *parser.SymbolInfo) types.Type {
+	t, ok := a.types.Resolve(sym.Type)
+	if !ok {
+		return nil
+	}
+	return t
+}
+
+// signature monta o tipo de uma função a partir da sua declaração. Parâmetros
+// e retorno de tipo desconhecido ficam nil
+func (a *Analyzer) signature(fd *parser.FunctionDeclaration) *types.Signature {
+	sig := &types.Signature{Params: make([]types.Type, len(fd.Parameters))}
+	for i, param := range fd.Parameters {
+		if t, ok := a.types.Resolve(param.Type); ok {
+			sig.Params[i] = t
+		}
+	}
+	if t, ok := a.types.Resolve(fd.ReturnType); ok {
+		sig.Result = t
+	}
+	return sig
+}
+
+func resultType(leftType, rightType types.Type) types.Type {
+	if types.Identical(leftType, types.Float) || types.Identical(rightType, types.Float) {
+		return types.Float
+	}
+	return types.Int
+}
+
+// checkExpression verifica uma expressão e registra o seu tipo
+func (a *Analyzer) checkExpression(expr parser.Expression) types.Type {
+	t := a.expressionType(expr)
+	if t != nil {
+		a.info.Types[expr] = t
+	}
+	return t
+}
+
+func (a *Analyzer) expressionType(expr parser.Expression) types.Type {
 	switch e := expr.(type) {
 
 	case *parser.Identifier:
 		return a.checkIdentifier(e)
 	case *parser.BinaryExpression:
 		return a.checkBinaryExpr(e)
+	case *parser.UnaryExpression:
+		return a.checkUnaryExpr(e)
 	case *parser.Number:
-		if e.Value == float64(int(e.Value)) {
-			return "int"
-		}
-		return "float"
+		return a.numberType(e, math.MaxInt32)
 	case *parser.BooleanLiteral:
-		return "bool"
+		return types.Bool
 	case *parser.StringLiteral:
-		return "string"
+		return types.String
+	case *parser.CallExpression:
+		return a.checkCallExpression(e)
+	case *parser.IndexExpression:
+		return a.checkIndexExpression(e)
+	case *parser.NewArrayExpression:
+		return a.checkNewArray(e)
+	case *parser.CastExpression:
+		return a.checkCast(e)
+	case *parser.MemberExpression:
+		return a.checkMemberExpression(e)
+	case *parser.StructLiteral:
+		return a.checkStructLiteral(e)
 	default:
 		a.addError(fmt.Sprintf("Tipo de expressão não suportado: %T", expr),
-			expr.GetToken().Line, expr.GetToken().Lexeme)
-		return ""
+			expr.GetToken())
+		return nil
 	}
 }
 
-// Modifique a verificação de identificador
-func (a *Analyzer) checkIdentifier(ident *parser.Identifier) string {
-	// Verifica se é uma função builtin
-	if a.isBuiltinFunction(ident.Name) {
-		return "void" // print não retorna valor
+// checkIndexExpression retorna o tipo dos elementos do array indexado
+func (a *Analyzer) checkIndexExpression(expr *parser.IndexExpression) types.Type {
+	arrayType := a.checkExpression(expr.Array)
+	indexType := a.checkExpression(expr.Index)
+	if indexType != nil && !types.Identical(indexType, types.Int) {
+		a.addError(fmt.Sprintf("Índice de array deve ser int, recebeu %s", indexType),
+			expr.Index.GetToken())
 	}
 
-	sym, exists := a.symbolTable.Resolve(ident.Name)
-	if !exists {
-		a.addError(fmt.Sprintf("Identificador não declarado: %s", ident.Name),
-			ident.Token.Line, ident.Token.Lexeme)
-		return ""
+	if arrayType == nil {
+		return nil
 	}
-	return sym.Type
+	array, ok := arrayType.(*types.Array)
+	if !ok {
+		a.addError(fmt.Sprintf("Indexação inválida: %s não é um array", arrayType), expr.Token)
+		return nil
+	}
+	return array.Elem
+}
+
+// checkNewArray verifica new T[n], que cria um T[] com n elementos
+func (a *Analyzer) checkNewArray(expr *parser.NewArrayExpression) types.Type {
+	sizeType := a.checkExpression(expr.Size)
+	if sizeType != nil && !types.Identical(sizeType, types.Int) {
+		a.addError(fmt.Sprintf("Tamanho do array deve ser int, recebeu %s", sizeType),
+			expr.Size.GetToken())
+	}
+
+	elem := a.resolveType(expr.ElementType, expr.Token)
+	if elem == nil {
+		return nil
+	}
+	return types.NewSlice(elem)
+}
+
+// checkCast verifica uma conversão explícita, que só existe entre int e float
+func (a *Analyzer) checkCast(expr *parser.CastExpression) types.Type {
+	valueType := a.checkExpression(expr.Value)
+	target, _ := a.types.Resolve(expr.Type)
+	if !types.IsNumeric(target) {
+		a.addError(fmt.Sprintf("Conversão para %s não é suportada; use int(...) ou float(...)", expr.Type),
+			expr.Token)
+		return nil
+	}
+
+	if valueType != nil && !types.ConvertibleTo(valueType, target) {
+		a.addError(fmt.Sprintf("Conversão inválida de %s para %s", valueType, target), expr.Token)
+		return target
+	}
+	a.convert(expr.Value, valueType, target)
+	return target
+}
+
+// checkMemberExpression retorna o tipo do campo acessado em p.x
+func (a *Analyzer) checkMemberExpression(expr *parser.MemberExpression) types.Type {
+	objectType := a.checkExpression(expr.Object)
+	if objectType == nil {
+		return nil
+	}
+
+	named, ok := objectType.(*types.Named)
+	if !ok {
+		a.addError(fmt.Sprintf("Acesso a campo inválido: %s não é uma struct", objectType), expr.Token)
+		return nil
+	}
+	field, _ := named.Field(expr.Field)
+	if field == nil {
+		a.addError(fmt.Sprintf("Struct '%s' não tem o campo '%s'", named.Name, expr.Field), expr.Token)
+		a.addNote(fmt.Sprintf("struct '%s' declarada aqui", named.Name), named.Decl.Token)
+		return nil
+	}
+	return field.Type
+}
+
+// checkStructLiteral verifica os campos de Tipo{campo: valor, ...}
+func (a *Analyzer) checkStructLiteral(lit *parser.StructLiteral) types.Type {
+	named, ok := a.types.Lookup(lit.TypeName)
+	if !ok {
+		a.addError(fmt.Sprintf("Tipo desconhecido: %s", lit.TypeName), lit.Token)
+		for _, fv := range lit.Fields {
+			a.checkExpression(fv.Value)
+		}
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for _, fv := range lit.Fields {
+		valueType := a.checkExpression(fv.Value)
+
+		field, _ := named.Field(fv.Name)
+		switch {
+		case field == nil:
+			a.addError(fmt.Sprintf("Struct '%s' não tem o campo '%s'", named.Name, fv.Name), fv.Token)
+			a.addNote(fmt.Sprintf("struct '%s' declarada aqui", named.Name), named.Decl.Token)
+		case seen[fv.Name]:
+			a.addError(fmt.Sprintf("Campo '%s' repetido no literal de %s", fv.Name, named.Name), fv.Token)
+		case !a.assignTo(fv.Value, valueType, field.Type):
+			a.addError(fmt.Sprintf("Tipo incompatível no campo '%s' de %s: esperado %s, recebeu %s",
+				fv.Name, named.Name, field.Type, valueType), fv.Token)
+			a.addNote(fmt.Sprintf("campo '%s' declarado aqui", field.Name), field.Decl.Token)
+			a.suggestCast(fv.Value, valueType, field.Type)
+		default:
+			a.checkArrayEscape(fv.Value, "guardado em um campo de struct")
+		}
+		seen[fv.Name] = true
+	}
+	return named
+}
+
+// Modifique a verificação de identificador
+func (a *Analyzer) checkIdentifier(ident *parser.Identifier) types.Type {
+	// Sem símbolo, o resolvedor já reportou o identificador (inclusive nomes
+	// de funções usados como valor)
+	if ident.Symbol == nil {
+		return nil
+	}
+	return a.symbolType(ident.Symbol)
 }
 
 func (a *Analyzer) checkStatement(stmt parser.Statement) {
@@ -157,6 +514,15 @@ func (a *Analyzer) checkStatement(stmt parser.Statement) {
 		a.checkVariableDecl(s)
 	case *parser.AssignmentStatement:
 		a.checkAssignment(s)
+	case *parser.IndexAssignmentStatement:
+		a.checkIndexAssignment(s)
+	case *parser.FieldAssignmentStatement:
+		a.checkFieldAssignment(s)
+	case *parser.StructDeclaration:
+		// Structs fora do nível superior já foram reportadas pelo resolvedor
+		if named, _ := a.types.Lookup(s.Name); named != nil && named.Decl == s {
+			a.checkStructDecl(named)
+		}
 	case *parser.IfStatement:
 		a.checkIfStatement(s)
 	case *parser.WhileStatement:
@@ -165,82 +531,143 @@ func (a *Analyzer) checkStatement(stmt parser.Statement) {
 		a.checkForStatement(s)
 	case *parser.BlockStatement:
 		a.checkBlockStatement(s)
-    case *parser.ExpressionStatement:
-        if call, ok := s.Expression.(*parser.CallExpression); ok && call.FunctionName == "print" {
-            a.checkPrintCall(call)
-        } else {
-            a.checkExpression(s.Expression)
-        }
-
-	case *parser.FunctionDeclaration:
-		a.symbolTable.PushScope()
-		// Verifica parâmetros
-		for _, param := range s.Parameters {
-			a.checkVariableDecl(param)
+	case *parser.ReturnStatement:
+		a.checkReturnStatement(s)
+	case *parser.BreakStatement:
+		if a.loopDepth == 0 {
+			a.addError("'break' só pode ser usado dentro de um laço", s.Token)
 		}
-		// Verifica corpo
-		for _, stmt := range s.Body {
-			a.checkStatement(stmt)
+	case *parser.ContinueStatement:
+		if a.loopDepth == 0 {
+			a.addError("'continue' só pode ser usado dentro de um laço", s.Token)
 		}
-		a.symbolTable.PopScope()
+	case *parser.ExpressionStatement:
+		a.checkExpression(s.Expression)
+
+	case *parser.FunctionDeclaration:
 		a.checkFunctionDecl(s)
 	default:
 		a.addError(fmt.Sprintf("Tipo de statement não suportado: %T", stmt),
-			stmt.GetToken().Line, stmt.GetToken().Lexeme)
+			stmt.GetToken())
 	}
 }
 
 func (a *Analyzer) checkIfStatement(ifStmt *parser.IfStatement) {
 	condType := a.checkExpression(ifStmt.Condition)
-	if condType != "bool" {
+	if !isCondition(condType) {
 		a.addError("Condição do if deve ser booleana",
-			ifStmt.Condition.GetToken().Line, ifStmt.Condition.GetToken().Lexeme)
+			ifStmt.Condition.GetToken())
 	}
 
-	a.symbolTable.PushScope()
-	if ifStmt.Body != nil {
-		a.checkBlockStatement(ifStmt.Body)
-	}
-	a.symbolTable.PopScope()
+	a.checkBlockStatement(ifStmt.Body)
+	a.checkBlockStatement(ifStmt.ElseBody)
+}
 
-	if ifStmt.ElseBody != nil {
-		a.symbolTable.PushScope()
-		a.checkBlockStatement(ifStmt.ElseBody)
-		a.symbolTable.PopScope()
-	}
+// isCondition indica se o tipo pode ser usado como condição. Tipos
+// desconhecidos já foram reportados
+func isCondition(t types.Type) bool {
+	return t == nil || types.Identical(t, types.Bool)
 }
 
-func (a *Analyzer) checkBinaryExpr(expr *parser.BinaryExpression) string {
+func (a *Analyzer) checkBinaryExpr(expr *parser.BinaryExpression) types.Type {
 	leftType := a.checkExpression(expr.Left)
 	rightType := a.checkExpression(expr.Right)
 
 	switch expr.Operator {
 	case "+", "-", "*", "/":
-		if !a.isNumeric(leftType) || !a.isNumeric(rightType) {
+		if leftType == nil || rightType == nil {
+			return nil
+		}
+		if !types.IsNumeric(leftType) || !types.IsNumeric(rightType) {
 			a.addError(fmt.Sprintf("Operação numérica inválida entre %s e %s",
-				leftType, rightType), expr.Token.Line, expr.Token.Lexeme)
-			return ""
+				leftType, rightType), expr.Token)
+			return nil
 		}
-		return a.resultType(leftType, rightType)
+		// Com um operando float, o outro é convertido e a operação é em float
+		result := resultType(leftType, rightType)
+		a.convert(expr.Left, leftType, result)
+		a.convert(expr.Right, rightType, result)
+		return result
 
 	case ">", "<", ">=", "<=", "==", "!=":
-		if !a.isCompatible(leftType, rightType) {
+		if leftType == nil || rightType == nil {
+			return types.Bool
+		}
+		comparable := types.Comparable(leftType, rightType)
+		if expr.Operator != "==" && expr.Operator != "!=" {
+			comparable = types.Ordered(leftType, rightType)
+		}
+		if !comparable {
 			a.addError(fmt.Sprintf("Comparação inválida entre %s e %s",
-				leftType, rightType), expr.Token.Line, expr.Token.Lexeme)
+				leftType, rightType), expr.Token)
+		} else if types.IsNumeric(leftType) {
+			common := resultType(leftType, rightType)
+			a.convert(expr.Left, leftType, common)
+			a.convert(expr.Right, rightType, common)
 		}
-		return "bool"
+		return types.Bool
 
 	case "&&", "||":
-		if leftType != "bool" || rightType != "bool" {
+		if !isCondition(leftType) || !isCondition(rightType) {
 			a.addError("Operadores lógicos exigem operandos booleanos",
-				expr.Token.Line, expr.Token.Lexeme)
+				expr.Token)
 		}
-		return "bool"
+		return types.Bool
 
 	default:
 		a.addError(fmt.Sprintf("Operador desconhecido: %s", expr.Operator),
-			expr.Token.Line, expr.Token.Lexeme)
-		return ""
+			expr.Token)
+		return nil
+	}
+}
+
+// numberType tipa um literal pela grafia: com ponto é float; sem ponto é int
+// e precisa caber em 32 bits. max é o maior valor aceito
+func (a *Analyzer) numberType(num *parser.Number, max float64) types.Type {
+	if num.IsFloat() {
+		return types.Float
+	}
+	if num.Value > max {
+		a.addError(fmt.Sprintf("Literal inteiro %s fora do intervalo de int (%d a %d)",
+			num.Token.Lexeme, math.MinInt32, math.MaxInt32), num.Token)
+	}
+	return types.Int
+}
+
+func (a *Analyzer) checkUnaryExpr(expr *parser.UnaryExpression) types.Type {
+	var operandType types.Type
+	if num, ok := expr.Right.(*parser.Number); ok && expr.Operator == "-" {
+		// O menor int, -2147483648, só pode ser escrito como literal negado
+		operandType = a.numberType(num, -math.MinInt32)
+		a.info.Types[num] = operandType
+	} else {
+		operandType = a.checkExpression(expr.Right)
+	}
+	if operandType == nil {
+		return nil
+	}
+
+	switch expr.Operator {
+	case "-":
+		// Preserva o tipo numérico: -int é int, -float é float
+		if !types.IsNumeric(operandType) {
+			a.addError(fmt.Sprintf("Operação numérica inválida: '-' aplicado a %s",
+				operandType), expr.Token)
+			return nil
+		}
+		return operandType
+
+	case "!":
+		if !types.Identical(operandType, types.Bool) {
+			a.addError(fmt.Sprintf("Operação lógica inválida: '!' aplicado a %s",
+				operandType), expr.Token)
+			return nil
+		}
+		return types.Bool
+
+	default:
+		a.addError(fmt.Sprintf("Operador desconhecido: %s", expr.Operator), expr.Token)
+		return nil
 	}
 }
 
@@ -248,25 +675,21 @@ func (a *Analyzer) checkBlockStatement(block *parser.BlockStatement) {
 	if block == nil {
 		return
 	}
-	a.symbolTable.PushScope()
 	for _, stmt := range block.Statements {
 		a.checkStatement(stmt)
 	}
-	a.symbolTable.PopScope()
 }
 
 func (a *Analyzer) checkWhileStatement(whileStmt *parser.WhileStatement) {
 	condType := a.checkExpression(whileStmt.Condition)
-	if condType != "bool" {
+	if !isCondition(condType) {
 		a.addError("Condição do while deve ser booleana",
-			whileStmt.Condition.GetToken().Line, whileStmt.Condition.GetToken().Lexeme)
+			whileStmt.Condition.GetToken())
 	}
 
-	a.symbolTable.PushScope()
-	if whileStmt.Body != nil {
-		a.checkBlockStatement(whileStmt.Body)
-	}
-	a.symbolTable.PopScope()
+	a.loopDepth++
+	a.checkBlockStatement(whileStmt.Body)
+	a.loopDepth--
 }
 
 func (a *Analyzer) checkForStatement(forStmt *parser.ForStatement) {
@@ -276,9 +699,9 @@ func (a *Analyzer) checkForStatement(forStmt *parser.ForStatement) {
 
 	if forStmt.Condition != nil {
 		condType := a.checkExpression(forStmt.Condition)
-		if condType != "bool" {
+		if !isCondition(condType) {
 			a.addError("Condição do for deve ser booleana",
-				forStmt.Condition.GetToken().Line, forStmt.Condition.GetToken().Lexeme)
+				forStmt.Condition.GetToken())
 		}
 	}
 
@@ -286,60 +709,301 @@ func (a *Analyzer) checkForStatement(forStmt *parser.ForStatement) {
 		a.checkStatement(forStmt.Update)
 	}
 
-	a.symbolTable.PushScope()
-	if forStmt.Body != nil {
-		a.checkBlockStatement(forStmt.Body)
-	}
-	a.symbolTable.PopScope()
+	a.loopDepth++
+	a.checkBlockStatement(forStmt.Body)
+	a.loopDepth--
 }
 
-// semantic/semantic_analyzer.go
-
-// semantic/semantic_analyzer.go
 func (a *Analyzer) checkFunctionDecl(fd *parser.FunctionDeclaration) {
-	// Registra função no escopo GLOBAL
-	a.symbolTable.Declare(fd.Name, parser.SymbolInfo{
-		Name:      fd.Name,
-		Type:      fd.ReturnType,
-		Category:  parser.Function,
-		DefinedAt: fd.Token.Line,
-	})
-
-	// Cria escopo LOCAL para parâmetros
-	a.symbolTable.PushScope()
+	if fd.Name == "main" {
+		a.checkMainSignature(fd)
+	}
 
-	// Registra parâmetros
+	// Arrays são recebidos e retornados como T[]: o tamanho vem junto do array
 	for _, param := range fd.Parameters {
-		a.symbolTable.Declare(param.Name, parser.SymbolInfo{
-			Name:      param.Name,
-			Type:      param.Type,
-			Category:  parser.Variable,
-			DefinedAt: param.Token.Line,
-		})
+		paramType := a.resolveType(param.Type, param.Token)
+		if array, ok := paramType.(*types.Array); ok && array.IsFixed() {
+			a.addError(fmt.Sprintf("Parâmetro '%s' não pode ser um array de tamanho fixo; use %s[]",
+				param.Name, array.Elem), param.Token)
+		}
 	}
+	returnType, ok := a.types.Resolve(fd.ReturnType)
+	if !ok {
+		a.addError(fmt.Sprintf("Tipo desconhecido: %s", fd.ReturnType), fd.Token)
+	} else if array, ok := returnType.(*types.Array); ok && array.IsFixed() {
+		a.addError(fmt.Sprintf("Função '%s' não pode retornar um array de tamanho fixo; use %s[]",
+			fd.Name, array.Elem), fd.Token)
+	}
+
+	// break/continue não atravessam a fronteira da função
+	outerLoopDepth, outerFunc := a.loopDepth, a.currentFunc
+	a.loopDepth, a.currentFunc = 0, fd
+	defer func() { a.loopDepth, a.currentFunc = outerLoopDepth, outerFunc }()
 
 	// Verifica corpo
 	for _, stmt := range fd.Body {
 		a.checkStatement(stmt)
 	}
 
-	a.symbolTable.PopScope()
+	if !types.Identical(returnType, types.Void) && !a.alwaysReturns(fd.Body) {
+		a.addError(fmt.Sprintf("Nem todos os caminhos da função '%s' retornam um valor", fd.Name),
+			fd.Token)
+	}
+}
+
+// checkMainSignature garante que main pode ser chamada pelo C runtime: sem
+// parâmetros e retornando int (código de saída) ou void (sai com 0)
+func (a *Analyzer) checkMainSignature(fd *parser.FunctionDeclaration) {
+	if len(fd.Parameters) > 0 {
+		a.addError("Função 'main' não pode ter parâmetros", fd.Token)
+	}
+	returnType, _ := a.types.Resolve(fd.ReturnType)
+	if !types.Identical(returnType, types.Int) && !types.Identical(returnType, types.Void) {
+		a.addError(fmt.Sprintf("Função 'main' deve retornar int ou void, não %s", fd.ReturnType),
+			fd.Token)
+	}
+}
+
+func (a *Analyzer) checkReturnStatement(ret *parser.ReturnStatement) {
+	fd := a.currentFunc
+	if fd == nil {
+		a.addError("'return' só pode ser usado dentro de uma função", ret.Token)
+		if ret.Value != nil {
+			a.checkExpression(ret.Value)
+		}
+		return
+	}
+
+	returnType := a.signature(fd).Result
+	if types.Identical(returnType, types.Void) {
+		if ret.Value != nil {
+			a.checkExpression(ret.Value)
+			a.addError(fmt.Sprintf("Função '%s' é void e não pode retornar um valor", fd.Name),
+				ret.Token)
+		}
+		return
+	}
+
+	if ret.Value == nil {
+		a.addError(fmt.Sprintf("Função '%s' deve retornar um valor do tipo %s", fd.Name, fd.ReturnType),
+			ret.Token)
+		return
+	}
+
+	valueType := a.checkExpression(ret.Value)
+	if !a.assignTo(ret.Value, valueType, returnType) {
+		a.addError(fmt.Sprintf("Tipo de retorno incompatível em '%s': esperado %s, recebeu %s",
+			fd.Name, fd.ReturnType, valueType), ret.Token)
+		a.suggestCast(ret.Value, valueType, returnType)
+		return
+	}
+	a.checkArrayEscape(ret.Value, "retornado")
+}
+
+// alwaysReturns indica se toda execução da lista de comandos termina em um
+// return (ou fica presa em um laço infinito sem break)
+func (a *Analyzer) alwaysReturns(stmts []parser.Statement) bool {
+	for _, stmt := range stmts {
+		if a.statementReturns(stmt) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Analyzer) statementReturns(stmt parser.Statement) bool {
+	switch s := stmt.(type) {
+	case *parser.ReturnStatement:
+		return true
+	case *parser.ExpressionStatement:
+		// exit encerra o processo, então nada depois dele é executado
+		call, ok := s.Expression.(*parser.CallExpression)
+		return ok && call.FunctionName == "exit"
+	case *parser.BlockStatement:
+		return s != nil && a.alwaysReturns(s.Statements)
+	case *parser.IfStatement:
+		// Sem else, o caminho em que a condição é falsa segue adiante
+		if s.ElseBody == nil || s.Body == nil {
+			return false
+		}
+		return a.alwaysReturns(s.Body.Statements) && a.alwaysReturns(s.ElseBody.Statements)
+	case *parser.WhileStatement:
+		// while (true) sem break nunca chega ao fim da função
+		return isTrueLiteral(s.Condition) && !containsBreak(s.Body)
+	case *parser.ForStatement:
+		return (s.Condition == nil || isTrueLiteral(s.Condition)) && !containsBreak(s.Body)
+	default:
+		return false
+	}
+}
+
+func isTrueLiteral(expr parser.Expression) bool {
+	lit, ok := expr.(*parser.BooleanLiteral)
+	return ok && lit.Value
+}
+
+// containsBreak procura um break que saia do laço dono do bloco, sem descer
+// em laços aninhados (cujos breaks só encerram a eles mesmos)
+func containsBreak(block *parser.BlockStatement) bool {
+	if block == nil {
+		return false
+	}
+	for _, stmt := range block.Statements {
+		switch s := stmt.(type) {
+		case *parser.BreakStatement:
+			return true
+		case *parser.BlockStatement:
+			if containsBreak(s) {
+				return true
+			}
+		case *parser.IfStatement:
+			if containsBreak(s.Body) || containsBreak(s.ElseBody) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
-func (a *Analyzer) isBuiltinFunction(name string) bool {
-	return name == "print"
+// checkCallExpression valida uma chamada contra a declaração da função
+// (aridade e tipo de cada argumento) e retorna o tipo de retorno dela
+func (a *Analyzer) checkCallExpression(call *parser.CallExpression) types.Type {
+	if call.FunctionName == "print" {
+		return a.checkPrintCall(call)
+	}
+	if call.FunctionName == "exit" {
+		return a.checkExitCall(call)
+	}
+	if call.FunctionName == "printf" {
+		return a.checkPrintfCall(call)
+	}
+	if call.FunctionName == "len" {
+		return a.checkLenCall(call)
+	}
+
+	// Sem símbolo, o resolvedor já reportou a chamada
+	if call.Symbol == nil {
+		a.checkArguments(call.Arguments)
+		return nil
+	}
+	fd := call.Symbol.Declaration.(*parser.FunctionDeclaration)
+	sig := a.signature(fd)
+
+	if len(call.Arguments) != len(fd.Parameters) {
+		a.addError(fmt.Sprintf("Função '%s' espera %d argumento(s), recebeu %d",
+			fd.Name, len(fd.Parameters), len(call.Arguments)), call.Token)
+		a.addNote(fmt.Sprintf("função '%s' declarada aqui", fd.Name), fd.Token)
+		a.checkArguments(call.Arguments)
+		return sig.Result
+	}
+
+	for i, arg := range call.Arguments {
+		argType := a.checkExpression(arg)
+		if !a.assignTo(arg, argType, sig.Params[i]) {
+			a.addError(fmt.Sprintf("Argumento %d de '%s' incompatível: esperado %s, recebeu %s",
+				i+1, fd.Name, fd.Parameters[i].Type, argType), arg.GetToken())
+			a.addNote(fmt.Sprintf("parâmetro '%s' declarado aqui", fd.Parameters[i].Name),
+				fd.Parameters[i].Token)
+			a.suggestCast(arg, argType, sig.Params[i])
+		}
+	}
+
+	return sig.Result
 }
 
-func (a *Analyzer) checkPrintCall(call *parser.CallExpression) string {
-    if len(call.Arguments) != 1 {
-        a.addError("print requer exatamente 1 argumento", call.Token.Line, call.Token.Lexeme)
-        return ""
-    }
-    
-    argType := a.checkExpression(call.Arguments[0])
-    if argType != "int" && argType != "float" && argType != "string" {
-        a.addError(fmt.Sprintf("print só suporta int, float ou string, recebeu %s", argType),
-            call.Token.Line, call.Token.Lexeme)
-    }
-    return "void"
-}
\ No newline at end of file
+// checkArguments verifica os argumentos de uma chamada que não pôde ser resolvida
+func (a *Analyzer) checkArguments(args []parser.Expression) {
+	for _, arg := range args {
+		a.checkExpression(arg)
+	}
+}
+
+func isBuiltinFunction(name string) bool {
+	return name == "print" || name == "printf" || name == "exit" || name == "len"
+}
+
+// checkLenCall verifica len(xs), que retorna a quantidade de elementos de um array
+func (a *Analyzer) checkLenCall(call *parser.CallExpression) types.Type {
+	if len(call.Arguments) != 1 {
+		a.addError("len requer exatamente 1 argumento", call.Token)
+		a.checkArguments(call.Arguments)
+		return types.Int
+	}
+
+	argType := a.checkExpression(call.Arguments[0])
+	if _, isArray := argType.(*types.Array); argType != nil && !isArray {
+		a.addError(fmt.Sprintf("len espera um array, recebeu %s", argType),
+			call.Arguments[0].GetToken())
+	}
+	return types.Int
+}
+
+func (a *Analyzer) checkExitCall(call *parser.CallExpression) types.Type {
+	if len(call.Arguments) != 1 {
+		a.addError("exit requer exatamente 1 argumento", call.Token)
+		a.checkArguments(call.Arguments)
+		return types.Void
+	}
+
+	argType := a.checkExpression(call.Arguments[0])
+	if argType != nil && !types.Identical(argType, types.Int) {
+		a.addError(fmt.Sprintf("exit espera um código de saída int, recebeu %s", argType),
+			call.Arguments[0].GetToken())
+	}
+	return types.Void
+}
+
+// checkPrintCall aceita qualquer quantidade de valores imprimíveis, escritos
+// separados por espaço
+func (a *Analyzer) checkPrintCall(call *parser.CallExpression) types.Type {
+	for _, arg := range call.Arguments {
+		argType := a.checkExpression(arg)
+		if basic, ok := argType.(*types.Basic); argType != nil && (!ok || basic.Kind == types.KindVoid) {
+			a.addError(fmt.Sprintf("print só suporta int, float, string ou bool, recebeu %s", argType),
+				arg.GetToken())
+		}
+	}
+	return types.Void
+}
+
+// checkPrintfCall valida os argumentos contra as diretivas do formato, que
+// precisa ser uma string literal para ser conhecido em tempo de compilação
+func (a *Analyzer) checkPrintfCall(call *parser.CallExpression) types.Type {
+	if len(call.Arguments) == 0 {
+		a.addError("printf requer uma string de formato", call.Token)
+		return types.Void
+	}
+
+	a.checkExpression(call.Arguments[0])
+	literal, ok := call.Arguments[0].(*parser.StringLiteral)
+	if !ok {
+		a.addError("O formato de printf deve ser uma string literal", call.Arguments[0].GetToken())
+		a.checkArguments(call.Arguments[1:])
+		return types.Void
+	}
+
+	directives, err := format.Parse(literal.Value)
+	if err != nil {
+		a.addError(fmt.Sprintf("Formato de printf inválido: %v", err), literal.Token)
+		a.checkArguments(call.Arguments[1:])
+		return types.Void
+	}
+
+	args := call.Arguments[1:]
+	if len(args) != len(directives) {
+		a.addError(fmt.Sprintf("O formato de printf espera %d argumento(s), recebeu %d",
+			len(directives), len(args)), call.Token)
+		a.checkArguments(args)
+		return types.Void
+	}
+
+	for i, arg := range args {
+		argType := a.checkExpression(arg)
+		expected := directives[i].Type()
+		if !a.assignTo(arg, argType, expected) {
+			a.addError(fmt.Sprintf("Argumento %d de printf incompatível com '%s': esperado %s, recebeu %s",
+				i+2, directives[i].Text, expected, argType), arg.GetToken())
+		}
+	}
+	return types.Void
+}
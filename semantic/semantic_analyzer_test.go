@@ -0,0 +1,211 @@
+package semantic
+
+import (
+	"simple-compiler/lexer"
+	"simple-compiler/parser"
+	"strings"
+	"testing"
+)
+
+// analyze executa o parser e a análise semântica, falhando o teste em erros
+// de sintaxe
+func analyze(t *testing.T, src string) []SemanticError {
+	t.Helper()
+
+	p := parser.New(lexer.Tokenize(src))
+	statements := p.Parse()
+	if len(p.Errors) > 0 {
+		t.Fatalf("erros de sintaxe: %v", p.Errors)
+	}
+	return New(statements).Analyze()
+}
+
+type semanticCase struct {
+	name string
+	src  string
+	want string // Trecho da mensagem de erro esperada; vazio quando não há erro
+}
+
+func runSemanticCases(t *testing.T, tests []semanticCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := analyze(t, tt.src)
+			if tt.want == "" {
+				if len(errs) > 0 {
+					t.Fatalf("erros inesperados: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("esperava 1 erro com %q, obteve %v", tt.want, errs)
+			}
+			if !strings.Contains(errs[0].Message, tt.want) {
+				t.Errorf("esperava erro com %q, obteve %q", tt.want, errs[0].Message)
+			}
+		})
+	}
+}
+
+func TestComparacoes(t *testing.T) {
+	runSemanticCases(t, []semanticCase{
+		{"int < int", "bool b = 1 < 2\n", ""},
+		{"int < float", "bool b = 1 < 2.5\n", ""},
+		{"float >= int", "bool b = 2.5 >= 1\n", ""},
+		{"bool == bool", "bool b = true == false\n", ""},
+		{"bool != bool", "bool b = true != false\n", ""},
+		{"string == string", "bool b = \"a\" == \"b\"\n", ""},
+		{"string != string", "bool b = \"a\" != \"b\"\n", ""},
+		{"bool < bool", "bool b = true < false\n", "Comparação inválida entre bool e bool"},
+		{"string < string", "bool b = \"a\" < \"b\"\n", "Comparação inválida entre string e string"},
+		{"string >= string", "bool b = \"a\" >= \"b\"\n", "Comparação inválida entre string e string"},
+		{"int == string", "bool b = 1 == \"a\"\n", "Comparação inválida entre int e string"},
+		{"bool == int", "bool b = true == 1\n", "Comparação inválida entre bool e int"},
+		{"arrays", "int[] a = new int[1]\nbool b = a == a\n", "Comparação inválida entre int[] e int[]"},
+	})
+}
+
+func TestConversoes(t *testing.T) {
+	runSemanticCases(t, []semanticCase{
+		{"int para float implícito", "int n = 3\nfloat f = n\nf = n * 2\n", ""},
+		{"argumento int para float", "func half(float x) float {\n\treturn x / 2\n}\nfloat h = half(3)\n", ""},
+		{"literal com ponto é float", "int x = 2.0\n", "não é possível atribuir float a int"},
+		{"float para int implícito", "float f = 2.5\nint x = f\n", "não é possível atribuir float a int"},
+		{"atribuição de float a int", "int x = 1\nx = 1.5\n", "Tipo incompatível em atribuição: int = float"},
+		{"retorno float em função int", "func f() int {\n\treturn 1.5\n}\n", "esperado int, recebeu float"},
+		{"int(...)", "float f = 2.7\nint x = int(f)\n", ""},
+		{"float(...)", "int n = 7\nfloat f = float(n) / 2\n", ""},
+		{"conversão sem efeito", "int x = int(3)\n", ""},
+		{"conversão para string", "int x = 1\nstring s = string(x)\n", "Conversão para string não é suportada"},
+		{"conversão de bool", "int x = int(true)\n", "Conversão inválida de bool para int"},
+		{"conversão de string", "float f = float(\"1.5\")\n", "Conversão inválida de string para float"},
+	})
+}
+
+func TestSugestaoDeConversao(t *testing.T) {
+	errs := analyze(t, "float f = 2.5\nint x = f\n")
+	if len(errs) != 1 {
+		t.Fatalf("esperava 1 erro, obteve %v", errs)
+	}
+	notes := errs[0].Notes
+	if len(notes) != 1 || !strings.Contains(notes[0].Message, "use int(...) para converter") {
+		t.Fatalf("esperava a sugestão de int(...), obteve %v", notes)
+	}
+	if notes[0].Line != 2 || notes[0].Token != "f" {
+		t.Errorf("sugestão na posição inesperada: %+v", notes[0])
+	}
+
+	// Sem conversão possível não há sugestão
+	errs = analyze(t, "bool b = true\nint x = b\n")
+	if len(errs) != 1 || len(errs[0].Notes) != 0 {
+		t.Errorf("esperava 1 erro sem sugestão, obteve %+v", errs)
+	}
+}
+
+func TestArrays(t *testing.T) {
+	runSemanticCases(t, []semanticCase{
+		{"array fixo e T[]", "int[3] a\nint[] b = a\nb = new int[2]\nprint(len(a), b[0])\n", ""},
+		{"índice float", "int[3] a\nprint(a[1.5])\n", "Índice de array deve ser int, recebeu float"},
+		{"tamanho float", "int[] a = new int[2.5]\n", "Tamanho do array deve ser int, recebeu float"},
+		{"indexar um int", "int x = 3\nprint(x[0])\n", "não é um array"},
+		{"len de um int", "int x = 3\nprint(len(x))\n", "len"},
+		{"atribuir a um array fixo", "int[3] a\nint[] b = new int[3]\na = b\n", "tamanho fixo não pode receber outro array"},
+		{"elemento com tipo errado", "int[3] a\na[1] = \"s\"\n", "Tipo incompatível em atribuição: int = string"},
+		{"array de void", "void[2] v\n", "Tipo desconhecido: void[2]"},
+		{"variável chamada len", "int len = 3\nprint(len)\n", ""},
+		{"variável len sombreia o builtin", "int[] xs = new int[2]\nint len = len(xs)\nprint(len + 1)\n", ""},
+	})
+}
+
+// Um array de tamanho fixo local vive na pilha da função e não pode ser usado
+// como T[] depois que ela retorna
+func TestArraysLocaisNaoEscapam(t *testing.T) {
+	const decls = "int[] g\nstruct S {\n\tint[] data\n}\nint[3] top\n"
+	runSemanticCases(t, []semanticCase{
+		{"retorno", decls + "func f() int[] {\n\tint[3] xs\n\txs[0] = 42\n\treturn xs\n}\n", "Array local 'xs' não pode ser retornado"},
+		{"global", decls + "func f() void {\n\tint[3] xs\n\tg = xs\n}\n", "Array local 'xs' não pode ser guardado na variável global 'g'"},
+		{"campo", decls + "func f(S s) void {\n\tint[3] xs\n\ts.data = xs\n}\n", "guardado em um campo de struct"},
+		{"literal de struct", decls + "func f() S {\n\tint[3] xs\n\treturn S{data: xs}\n}\n", "guardado em um campo de struct"},
+		{"array em bloco interno", decls + "func f() int[] {\n\t{\n\t\tint[2] xs\n\t\treturn xs\n\t}\n}\n", "Array local 'xs' não pode ser retornado"},
+		{"array global", decls + "func f() int[] {\n\treturn top\n}\n", ""},
+		{"array do heap", decls + "func f() int[] {\n\tint[] xs = new int[3]\n\tg = xs\n\treturn xs\n}\n", ""},
+		{"uso local como T[]", decls + "func f() int {\n\tint[3] xs\n\tint[] alias = xs\n\tS s = S{data: xs}\n\treturn len(alias) + len(s.data)\n}\n", "guardado em um campo de struct"},
+		{"argumento", decls + "func soma(int[] xs) int {\n\treturn len(xs)\n}\nfunc f() int {\n\tint[3] xs\n\treturn soma(xs)\n}\n", ""},
+		{"nível superior", decls + "{\n\tint[2] xs\n\tg = xs\n}\nprint(len(g))\n", ""},
+	})
+
+	errs := analyze(t, decls+"func f() int[] {\n\tint[3] xs\n\treturn xs\n}\n")
+	if len(errs) != 1 || len(errs[0].Notes) != 1 ||
+		!strings.Contains(errs[0].Notes[0].Message, "use new int[3] para criar o array no heap") {
+		t.Errorf("esperava a sugestão de new int[3], obteve %+v", errs)
+	}
+}
+
+func TestLiteraisInteiros(t *testing.T) {
+	runSemanticCases(t, []semanticCase{
+		{"maior int", "int x = 2147483647\n", ""},
+		{"menor int", "int x = -2147483648\n", ""},
+		{"acima do maior int", "int x = 2147483648\n", "Literal inteiro 2147483648 fora do intervalo de int"},
+		{"abaixo do menor int", "int x = -2147483649\n", "Literal inteiro 2147483649 fora do intervalo de int"},
+		{"muito grande", "int x = 99999999999\n", "Literal inteiro 99999999999 fora do intervalo de int"},
+		{"dentro de expressão", "int x = 1 + 3000000000\n", "fora do intervalo de int"},
+		{"float grande", "float f = 99999999999.0\n", ""},
+	})
+}
+
+func TestStructs(t *testing.T) {
+	const point = "struct Point {\n\tint x\n\tfloat y\n}\n"
+	runSemanticCases(t, []semanticCase{
+		{"literal e acesso a campos", point + "Point p = Point{x: 1, y: 2.5}\np.x = p.x + 1\nfloat f = p.y\n", ""},
+		{"int convertido no campo float", point + "Point p = Point{x: 1, y: 2}\np.y = 3\n", ""},
+		{"literal parcial", point + "Point p = Point{y: 1.5}\n", ""},
+		{"struct usada antes da declaração", "func origin() Point {\n\treturn Point{}\n}\n" + point, ""},
+		{"struct como parâmetro", point + "func norm(Point p) float {\n\treturn p.x * p.y\n}\n", ""},
+		{"struct com campo struct", point + "struct Line {\n\tPoint from\n\tPoint to\n}\nLine l\nl.to.x = 3\n", ""},
+		{"campo inexistente", point + "Point p\nint z = p.z\n", "Struct 'Point' não tem o campo 'z'"},
+		{"campo inexistente no literal", point + "Point p = Point{z: 1}\n", "Struct 'Point' não tem o campo 'z'"},
+		{"campo repetido no literal", point + "Point p = Point{x: 1, x: 2}\n", "Campo 'x' repetido no literal de Point"},
+		{"tipo errado no literal", point + "Point p = Point{x: 1.5}\n", "Tipo incompatível no campo 'x' de Point: esperado int, recebeu float"},
+		{"tipo errado na atribuição", point + "Point p\np.x = \"a\"\n", "Tipo incompatível em atribuição: int = string"},
+		{"acesso a campo de int", "int n\nint m = n.x\n", "Acesso a campo inválido: int não é uma struct"},
+		{"struct desconhecida", "Point{x: 1}\n", "Tipo desconhecido: Point"},
+		{"struct repetida", point + point, "Struct 'Point' já declarada"},
+		{"campo repetido", "struct S {\n\tint a\n\tfloat a\n}\n", "Campo 'a' já declarado na struct 'S'"},
+		{"campo de tipo desconhecido", "struct S {\n\tfoo a\n}\n", "Tipo desconhecido: foo"},
+		{"campo array fixo", "struct S {\n\tint[3] a\n}\n", "Campo 'a' não pode ser um array de tamanho fixo; use int[]"},
+		{"struct recursiva", "struct S {\n\tS next\n}\n", "Struct 'S' não pode conter a si mesma (campo 'next')"},
+		{"recursão por slice", "struct S {\n\tS[] children\n}\n", ""},
+	})
+}
+
+func TestBreakEContinue(t *testing.T) {
+	runSemanticCases(t, []semanticCase{
+		{"break no while", "while (true) {\n\tbreak\n}\n", ""},
+		{"continue no for", "for (int i = 0; i < 3; i = i + 1) {\n\tcontinue\n}\n", ""},
+		{"break dentro de if no laço", "while (true) {\n\tif (true) {\n\t\tbreak\n\t}\n}\n", ""},
+		{"break fora de laço", "break\n", "'break' só pode ser usado dentro de um laço"},
+		{"continue fora de laço", "if (true) {\n\tcontinue\n}\n", "'continue' só pode ser usado dentro de um laço"},
+		{"break no corpo de função", "func f() void {\n\tbreak\n}\nwhile (true) {\n\tf()\n}\n", "'break' só pode ser usado dentro de um laço"},
+		{"laço infinito sem break retorna", "func f() int {\n\twhile (true) {\n\t}\n}\n", ""},
+		{"laço infinito com break não retorna", "func f() int {\n\twhile (true) {\n\t\tbreak\n\t}\n}\n", "Nem todos os caminhos da função 'f' retornam um valor"},
+		{"break de laço aninhado não conta", "func f() int {\n\tfor (;;) {\n\t\twhile (true) {\n\t\t\tbreak\n\t\t}\n\t}\n}\n", ""},
+	})
+}
+
+func TestPrint(t *testing.T) {
+	runSemanticCases(t, []semanticCase{
+		{"vários argumentos", "print(1, 2.5, \"a\", true)\n", ""},
+		{"sem argumentos", "print()\n", ""},
+		{"array", "int[] xs = new int[1]\nprint(xs)\n", "print só suporta int, float, string ou bool, recebeu int[]"},
+		{"void", "func f() void {\n}\nprint(f())\n", "print só suporta int, float, string ou bool, recebeu void"},
+		{"printf", "printf(\"%d %5.2f %s %t\\n\", 1, 2.5, \"a\", false)\n", ""},
+		{"printf com int em %f", "printf(\"%f\", 1)\n", ""},
+		{"printf sem formato", "printf()\n", "printf requer uma string de formato"},
+		{"printf com formato variável", "string f = \"%d\"\nprintf(f, 1)\n", "O formato de printf deve ser uma string literal"},
+		{"printf com diretiva inválida", "printf(\"%x\", 1)\n", "Formato de printf inválido: diretiva desconhecida '%x'"},
+		{"printf com argumentos a menos", "printf(\"%d %d\", 1)\n", "O formato de printf espera 2 argumento(s), recebeu 1"},
+		{"printf com argumentos a mais", "printf(\"%d\", 1, 2)\n", "O formato de printf espera 1 argumento(s), recebeu 2"},
+		{"printf com tipo errado", "printf(\"%d\", 2.5)\n", "Argumento 2 de printf incompatível com '%d': esperado int, recebeu float"},
+		{"printf com bool em %s", "printf(\"x %s\", true)\n", "Argumento 2 de printf incompatível com '%s': esperado string, recebeu bool"},
+	})
+}
@@ -6,9 +6,10 @@ type TokenType string
 // Token representa um único token gerado pelo lexer.
 type Token struct {
 	Type   TokenType
-	Lexeme string
-	Line   int // linha para mapear os erros
-	Column int // coluna para mapear os erros
+	Lexeme string // Texto do token como escrito no código-fonte
+	Value  string // Conteúdo de literais string, com os escapes já interpretados
+	Line   int    // linha para mapear os erros
+	Column int    // coluna para mapear os erros
 }
 
 // Definição dos tipos de tokens.
@@ -29,6 +30,8 @@ const (
 	RPAREN         TokenType = "RPAREN"     // )
 	LBRACE         TokenType = "LBRACE"     // {
 	RBRACE         TokenType = "RBRACE"     // }
+	LBRACKET       TokenType = "LBRACKET"   // [
+	RBRACKET       TokenType = "RBRACKET"   // ]
 	IF             TokenType = "IF"         // if
 	ELSE           TokenType = "ELSE"       // else
 	WHILE          TokenType = "WHILE"      // while
@@ -50,5 +53,10 @@ const (
 	FUNC           TokenType = "FUNC"  // func declaration
 	COMMA          TokenType = "COMMA" //  ,
 	COLON          TokenType = "COLON" // :
+	DOT            TokenType = "DOT"   // .
 	PRINT          TokenType = "PRINT" // print
+	BREAK          TokenType = "BREAK"    // break
+	CONTINUE       TokenType = "CONTINUE" // continue
+	NEW            TokenType = "NEW"      // new
+	STRUCT         TokenType = "STRUCT"   // struct
 )
\ No newline at end of file
@@ -0,0 +1,26 @@
+package types
+
+import "simple-compiler/parser"
+
+// Info guarda o resultado da análise semântica usado pela geração de código:
+// o tipo de cada expressão verificada, as conversões implícitas e as structs
+// declaradas no arquivo
+type Info struct {
+	Types       map[parser.Expression]Type
+	Conversions map[parser.Expression]Type // Tipo para o qual o valor da expressão é convertido
+	Structs     *Table
+}
+
+func NewInfo() *Info {
+	return &Info{
+		Types:       make(map[parser.Expression]Type),
+		Conversions: make(map[parser.Expression]Type),
+		Structs:     NewTable(),
+	}
+}
+
+// TypeOf retorna o tipo registrado para a expressão, ou nil quando ela não foi
+// verificada ou tem tipo desconhecido
+func (info *Info) TypeOf(expr parser.Expression) Type {
+	return info.Types[expr]
+}
@@ -0,0 +1,148 @@
+package types
+
+import (
+	"simple-compiler/parser"
+	"strconv"
+	"strings"
+)
+
+// Named é um tipo struct declarado pelo usuário
+type Named struct {
+	Name   string
+	Fields []*Field
+	Decl   *parser.StructDeclaration
+}
+
+// Field é um campo de struct. Type é nil quando o tipo escrito é desconhecido
+type Field struct {
+	Name string
+	Type Type
+	Decl *parser.VariableDeclaration
+}
+
+func (n *Named) String() string { return n.Name }
+
+// LLVM retorna o nome do tipo LLVM da struct, definido com LLVMBody. O
+// prefixo segue o que o clang faz com as structs de C
+func (n *Named) LLVM() string { return "%struct." + n.Name }
+
+// LLVMBody retorna a definição do tipo LLVM da struct: { campo1, campo2, ... }
+func (n *Named) LLVMBody() string {
+	fields := make([]string, len(n.Fields))
+	for i, field := range n.Fields {
+		fields[i] = field.Type.LLVM()
+	}
+	return "{ " + strings.Join(fields, ", ") + " }"
+}
+
+// Field retorna o campo com o nome informado e a sua posição na struct
+func (n *Named) Field(name string) (*Field, int) {
+	for i, field := range n.Fields {
+		if field.Name == name {
+			return field, i
+		}
+	}
+	return nil, -1
+}
+
+// Table guarda os tipos com nome do programa e traduz os nomes de tipos, como
+// escritos no código, para Type. Os tipos não têm escopo: são declarados no
+// nível superior e visíveis em todo o arquivo, independente da ordem
+type Table struct {
+	named map[string]*Named
+	order []*Named
+}
+
+func NewTable() *Table {
+	return &Table{named: make(map[string]*Named)}
+}
+
+// DeclareStructs registra as structs de nível superior e resolve os tipos dos
+// seus campos. Todas são registradas antes, pois um campo pode usar uma
+// struct declarada mais adiante. Retorna as declarações com nome repetido,
+// que ficam de fora da tabela
+func (t *Table) DeclareStructs(stmts []parser.Statement) []*parser.StructDeclaration {
+	var declared []*Named
+	var duplicates []*parser.StructDeclaration
+	for _, stmt := range stmts {
+		sd, ok := stmt.(*parser.StructDeclaration)
+		if !ok {
+			continue
+		}
+		if _, exists := t.named[sd.Name]; exists {
+			duplicates = append(duplicates, sd)
+			continue
+		}
+		named := &Named{Name: sd.Name, Decl: sd}
+		t.named[sd.Name] = named
+		t.order = append(t.order, named)
+		declared = append(declared, named)
+	}
+
+	for _, named := range declared {
+		for _, field := range named.Decl.Fields {
+			fieldType, _ := t.Resolve(field.Type)
+			named.Fields = append(named.Fields, &Field{Name: field.Name, Type: fieldType, Decl: field})
+		}
+	}
+	return duplicates
+}
+
+// Lookup procura a struct com o nome informado
+func (t *Table) Lookup(name string) (*Named, bool) {
+	named, exists := t.named[name]
+	return named, exists
+}
+
+// Structs retorna as structs na ordem em que foram declaradas
+func (t *Table) Structs() []*Named {
+	return t.order
+}
+
+// Resolve traduz um nome de tipo, como "int", "Point", "float[10]" ou
+// "int[]". Retorna false para tipos desconhecidos e arrays de void
+func (t *Table) Resolve(name string) (Type, bool) {
+	if elemName, size, isArray := splitArrayName(name); isArray {
+		elem, ok := t.Resolve(elemName)
+		if !ok || !IsValue(elem) {
+			return nil, false
+		}
+		return &Array{Elem: elem, Len: size}, true
+	}
+
+	switch name {
+	case "int":
+		return Int, true
+	case "float":
+		return Float, true
+	case "bool":
+		return Bool, true
+	case "string":
+		return String, true
+	case "void":
+		return Void, true
+	}
+	if named, ok := t.named[name]; ok {
+		return named, true
+	}
+	return nil, false
+}
+
+// splitArrayName separa um nome de array no nome dos elementos e no tamanho:
+// "int[10]" retorna ("int", 10, true) e "int[]" retorna ("int", -1, true)
+func splitArrayName(name string) (elem string, size int, isArray bool) {
+	open := strings.IndexByte(name, '[')
+	if open < 0 || !strings.HasSuffix(name, "]") {
+		return name, 0, false
+	}
+
+	sizeText := name[open+1 : len(name)-1]
+	if sizeText == "" {
+		return name[:open], -1, true
+	}
+	size, err := strconv.Atoi(sizeText)
+	if err != nil {
+		return name, 0, false
+	}
+	return name[:open], size, true
+}
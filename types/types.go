@@ -0,0 +1,203 @@
+// Package types representa os tipos da linguagem: primitivos, arrays,
+// ponteiros, structs (tipos com nome) e assinaturas de função. Define as
+// regras entre eles, usadas pela análise semântica, e a representação de cada
+// um no LLVM IR, usada pelo gerador de código.
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type é um tipo da linguagem
+type Type interface {
+	String() string // Como o tipo é escrito no código-fonte
+	LLVM() string   // Tipo correspondente no LLVM IR
+}
+
+// BasicKind identifica um tipo primitivo
+type BasicKind int
+
+const (
+	KindInt BasicKind = iota
+	KindFloat
+	KindBool
+	KindString
+	KindVoid
+)
+
+// Basic é um tipo primitivo. Existe uma única instância de cada um
+type Basic struct {
+	Kind BasicKind
+	name string
+	llvm string
+}
+
+func (b *Basic) String() string { return b.name }
+func (b *Basic) LLVM() string   { return b.llvm }
+
+var (
+	Int    = &Basic{Kind: KindInt, name: "int", llvm: "i32"}
+	Float  = &Basic{Kind: KindFloat, name: "float", llvm: "float"}
+	Bool   = &Basic{Kind: KindBool, name: "bool", llvm: "i1"}
+	String = &Basic{Kind: KindString, name: "string", llvm: "i8*"}
+	Void   = &Basic{Kind: KindVoid, name: "void", llvm: "void"}
+)
+
+// Array é um array de elementos Elem. Com Len >= 0 o tamanho é fixo e faz
+// parte do tipo (int[10]); com Len < 0 (int[]) o tamanho só é conhecido em
+// tempo de execução e o valor carrega o tamanho junto do ponteiro para os
+// elementos
+type Array struct {
+	Elem Type
+	Len  int
+}
+
+// NewSlice cria o tipo T[], de tamanho definido em tempo de execução
+func NewSlice(elem Type) *Array {
+	return &Array{Elem: elem, Len: -1}
+}
+
+// IsFixed indica se o tamanho faz parte do tipo
+func (a *Array) IsFixed() bool { return a.Len >= 0 }
+
+func (a *Array) String() string {
+	if a.IsFixed() {
+		return fmt.Sprintf("%s[%d]", a.Elem, a.Len)
+	}
+	return a.Elem.String() + "[]"
+}
+
+// LLVM retorna [N x T] para arrays de tamanho fixo e o par { i32, T* }
+// (tamanho e elementos) para T[]
+func (a *Array) LLVM() string {
+	if a.IsFixed() {
+		return fmt.Sprintf("[%d x %s]", a.Len, a.Elem.LLVM())
+	}
+	return fmt.Sprintf("{ i32, %s }", (&Pointer{Elem: a.Elem}).LLVM())
+}
+
+// Pointer é um ponteiro para Elem. A linguagem não tem ponteiros explícitos;
+// eles aparecem na representação de outros tipos, como os elementos de T[]
+type Pointer struct {
+	Elem Type
+}
+
+func (p *Pointer) String() string { return p.Elem.String() + "*" }
+func (p *Pointer) LLVM() string   { return p.Elem.LLVM() + "*" }
+
+// Signature é o tipo de uma função
+type Signature struct {
+	Params []Type
+	Result Type
+}
+
+func (s *Signature) String() string {
+	params := make([]string, len(s.Params))
+	for i, param := range s.Params {
+		params[i] = param.String()
+	}
+	return fmt.Sprintf("func(%s) %s", strings.Join(params, ", "), s.Result)
+}
+
+func (s *Signature) LLVM() string {
+	params := make([]string, len(s.Params))
+	for i, param := range s.Params {
+		params[i] = param.LLVM()
+	}
+	return fmt.Sprintf("%s (%s)", s.Result.LLVM(), strings.Join(params, ", "))
+}
+
+// Identical indica se dois tipos são o mesmo tipo
+func Identical(x, y Type) bool {
+	switch x := x.(type) {
+	case *Basic:
+		y, ok := y.(*Basic)
+		return ok && x.Kind == y.Kind
+	case *Array:
+		y, ok := y.(*Array)
+		return ok && x.Len == y.Len && Identical(x.Elem, y.Elem)
+	case *Pointer:
+		y, ok := y.(*Pointer)
+		return ok && Identical(x.Elem, y.Elem)
+	case *Named:
+		// Cada struct é declarada uma única vez
+		return x == y
+	case *Signature:
+		y, ok := y.(*Signature)
+		if !ok || len(x.Params) != len(y.Params) || !Identical(x.Result, y.Result) {
+			return false
+		}
+		for i := range x.Params {
+			if !Identical(x.Params[i], y.Params[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// AssignableTo indica se um valor do tipo value pode ser guardado em uma
+// variável, parâmetro ou campo do tipo target
+func AssignableTo(value, target Type) bool {
+	// Arrays de tamanho fixo não podem receber outro array
+	if array, ok := target.(*Array); ok && array.IsFixed() {
+		return false
+	}
+	if Identical(value, target) {
+		return true
+	}
+
+	// int é convertido implicitamente para float; o contrário perde a parte
+	// fracionária e exige uma conversão explícita, int(x)
+	if Identical(value, Int) && Identical(target, Float) {
+		return true
+	}
+
+	// Um T[] aceita qualquer array de T
+	if target, ok := target.(*Array); ok {
+		value, ok := value.(*Array)
+		return ok && Identical(value.Elem, target.Elem)
+	}
+	return false
+}
+
+// ConvertibleTo indica se uma conversão explícita, como int(x) ou float(n),
+// aceita um valor do tipo value. Só existem conversões entre números
+func ConvertibleTo(value, target Type) bool {
+	return IsNumeric(value) && IsNumeric(target)
+}
+
+// Comparable indica se == e != aceitam os dois tipos: dois números ou dois
+// valores do mesmo tipo primitivo (exceto void). Strings são comparadas pelo
+// conteúdo
+func Comparable(x, y Type) bool {
+	if IsNumeric(x) && IsNumeric(y) {
+		return true
+	}
+	basic, ok := x.(*Basic)
+	return ok && basic.Kind != KindVoid && Identical(x, y)
+}
+
+// Ordered indica se <, >, <= e >= aceitam os dois tipos: só números
+func Ordered(x, y Type) bool {
+	return IsNumeric(x) && IsNumeric(y)
+}
+
+// IsNumeric indica se o tipo é int ou float
+func IsNumeric(t Type) bool {
+	return Identical(t, Int) || Identical(t, Float)
+}
+
+// IsValue indica se o tipo pode ser usado em variáveis, parâmetros, campos e
+// elementos de arrays: todos, exceto void e funções
+func IsValue(t Type) bool {
+	switch t := t.(type) {
+	case *Basic:
+		return t.Kind != KindVoid
+	case *Signature:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,209 @@
+package types
+
+import (
+	"simple-compiler/parser"
+	"testing"
+)
+
+var (
+	fixedInts  = &Array{Elem: Int, Len: 3}
+	fixedInts4 = &Array{Elem: Int, Len: 4}
+	sliceInts  = NewSlice(Int)
+	sliceFlts  = NewSlice(Float)
+	point      = &Named{Name: "Point"}
+	otherPoint = &Named{Name: "Point"}
+)
+
+func TestIdentical(t *testing.T) {
+	tests := []struct {
+		x, y Type
+		want bool
+	}{
+		{Int, Int, true},
+		{Int, Float, false},
+		{String, String, true},
+		{fixedInts, &Array{Elem: Int, Len: 3}, true},
+		{fixedInts, fixedInts4, false},
+		{fixedInts, sliceInts, false},
+		{sliceInts, NewSlice(Int), true},
+		{sliceInts, sliceFlts, false},
+		{&Pointer{Elem: Int}, &Pointer{Elem: Int}, true},
+		{&Pointer{Elem: Int}, &Pointer{Elem: Bool}, false},
+		{point, point, true},
+		{point, otherPoint, false},
+		{&Signature{Params: []Type{Int}, Result: Void}, &Signature{Params: []Type{Int}, Result: Void}, true},
+		{&Signature{Params: []Type{Int}, Result: Void}, &Signature{Params: []Type{Float}, Result: Void}, false},
+		{&Signature{Params: []Type{Int}, Result: Void}, &Signature{Params: []Type{Int}, Result: Int}, false},
+	}
+
+	for _, tt := range tests {
+		if got := Identical(tt.x, tt.y); got != tt.want {
+			t.Errorf("Identical(%s, %s) = %v, esperava %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestAssignableTo(t *testing.T) {
+	tests := []struct {
+		value, target Type
+		want          bool
+	}{
+		{Int, Int, true},
+		{Int, Float, true},
+		{Float, Int, false},
+		{Bool, Int, false},
+		{String, String, true},
+		{Int, String, false},
+		{fixedInts, sliceInts, true},
+		{sliceInts, sliceInts, true},
+		{fixedInts, sliceFlts, false},
+		{fixedInts, fixedInts, false},
+		{sliceInts, fixedInts, false},
+		{point, point, true},
+		{point, otherPoint, false},
+	}
+
+	for _, tt := range tests {
+		if got := AssignableTo(tt.value, tt.target); got != tt.want {
+			t.Errorf("AssignableTo(%s, %s) = %v, esperava %v", tt.value, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestConvertibleTo(t *testing.T) {
+	tests := []struct {
+		value, target Type
+		want          bool
+	}{
+		{Int, Float, true},
+		{Float, Int, true},
+		{Int, Int, true},
+		{Bool, Int, false},
+		{Float, String, false},
+		{String, Int, false},
+	}
+
+	for _, tt := range tests {
+		if got := ConvertibleTo(tt.value, tt.target); got != tt.want {
+			t.Errorf("ConvertibleTo(%s, %s) = %v, esperava %v", tt.value, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestComparableEOrdered(t *testing.T) {
+	tests := []struct {
+		x, y       Type
+		comparable bool
+		ordered    bool
+	}{
+		{Int, Int, true, true},
+		{Int, Float, true, true},
+		{Float, Float, true, true},
+		{Bool, Bool, true, false},
+		{String, String, true, false},
+		{Bool, Int, false, false},
+		{String, Int, false, false},
+		{Void, Void, false, false},
+		{sliceInts, sliceInts, false, false},
+		{point, point, false, false},
+	}
+
+	for _, tt := range tests {
+		if got := Comparable(tt.x, tt.y); got != tt.comparable {
+			t.Errorf("Comparable(%s, %s) = %v, esperava %v", tt.x, tt.y, got, tt.comparable)
+		}
+		if got := Ordered(tt.x, tt.y); got != tt.ordered {
+			t.Errorf("Ordered(%s, %s) = %v, esperava %v", tt.x, tt.y, got, tt.ordered)
+		}
+	}
+}
+
+func TestLLVM(t *testing.T) {
+	tests := []struct {
+		typ  Type
+		want string
+	}{
+		{Int, "i32"},
+		{Float, "float"},
+		{Bool, "i1"},
+		{String, "i8*"},
+		{Void, "void"},
+		{fixedInts, "[3 x i32]"},
+		{sliceFlts, "{ i32, float* }"},
+		{&Pointer{Elem: Int}, "i32*"},
+		{point, "%struct.Point"},
+		{&Signature{Params: []Type{Int, String}, Result: Bool}, "i1 (i32, i8*)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.typ.LLVM(); got != tt.want {
+			t.Errorf("%s.LLVM() = %q, esperava %q", tt.typ, got, tt.want)
+		}
+	}
+}
+
+func TestTableResolve(t *testing.T) {
+	table := NewTable()
+	duplicates := table.DeclareStructs([]parser.Statement{
+		&parser.StructDeclaration{Name: "Line", Fields: []*parser.VariableDeclaration{
+			{Type: "Point", Name: "from"},
+			{Type: "Point", Name: "to"},
+		}},
+		&parser.StructDeclaration{Name: "Point", Fields: []*parser.VariableDeclaration{
+			{Type: "int", Name: "x"},
+			{Type: "float[]", Name: "ys"},
+		}},
+		&parser.StructDeclaration{Name: "Point"},
+	})
+	if len(duplicates) != 1 || duplicates[0].Name != "Point" {
+		t.Errorf("esperava a segunda Point como repetida, obteve %v", duplicates)
+	}
+
+	pointType, ok := table.Lookup("Point")
+	if !ok {
+		t.Fatalf("Point não foi registrada")
+	}
+	lineType, _ := table.Lookup("Line")
+	// Line usa Point antes da declaração
+	if field, index := lineType.Field("to"); field == nil || index != 1 || field.Type != Type(pointType) {
+		t.Errorf("campo Line.to inesperado: %v, %d", field, index)
+	}
+	if got := pointType.LLVMBody(); got != "{ i32, { i32, float* } }" {
+		t.Errorf("Point.LLVMBody() = %q", got)
+	}
+	if structs := table.Structs(); len(structs) != 2 || structs[0] != lineType || structs[1] != pointType {
+		t.Errorf("ordem das structs inesperada: %v", structs)
+	}
+
+	tests := []struct {
+		name string
+		want Type // nil quando o tipo é desconhecido
+	}{
+		{"int", Int},
+		{"float", Float},
+		{"bool", Bool},
+		{"string", String},
+		{"void", Void},
+		{"Point", pointType},
+		{"int[10]", &Array{Elem: Int, Len: 10}},
+		{"string[]", NewSlice(String)},
+		{"Point[2]", &Array{Elem: pointType, Len: 2}},
+		{"void[2]", nil},
+		{"Unknown", nil},
+		{"Unknown[]", nil},
+		{"int[x]", nil},
+	}
+
+	for _, tt := range tests {
+		got, ok := table.Resolve(tt.name)
+		if tt.want == nil {
+			if ok {
+				t.Errorf("Resolve(%q) = %s, esperava um tipo desconhecido", tt.name, got)
+			}
+			continue
+		}
+		if !ok || !Identical(got, tt.want) {
+			t.Errorf("Resolve(%q) = %v, %v, esperava %s", tt.name, got, ok, tt.want)
+		}
+	}
+}